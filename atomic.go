@@ -0,0 +1,118 @@
+package azfile
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	pathpkg "path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+// atomicTempSuffix is the suffix of the temporary files created by atomic writes,
+// so that they could be recognized and cleaned up if the process crashed.
+const atomicTempSuffix = ".azfile-tmp"
+
+// atomicTempPath will return a temporary path in the same directory of path, so
+// that the rename doesn't move the file across directories.
+//
+// The name is hashed instead of being copied, so that the temporary name never
+// exceeds the 255 characters limit of path components while the name is long.
+func atomicTempPath(path string) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	dir, name := pathpkg.Split(path)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprintf("%s.%016x.%s%s", dir, h.Sum64(), hex.EncodeToString(buf), atomicTempSuffix), nil
+}
+
+// writeAtomic will upload the content to a temporary file, and rename it over path
+// only after the upload succeeded, so that readers never observe a half-written file.
+//
+// The conditions and lease are applied on path, which is leased while uploading so
+// that it could not be changed by others before replaced. With create_only, the rename
+// fails if path has been created by others, so that existing files are never replaced.
+func (s *Storage) writeAtomic(ctx context.Context, path string, r io.Reader, size int64, opt pairStorageWrite) (n int64, err error) {
+	// The content of temporary file could not be resumed or patched in place.
+	if opt.HasOffset || opt.HasCheckpointStore {
+		return 0, fmt.Errorf("%w: atomic write with offset or checkpoint", services.ErrCapabilityInsufficient)
+	}
+	if strings.HasSuffix(path, "/") {
+		return 0, fmt.Errorf("atomic write into directory %s", path)
+	}
+
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+	}
+	// Check the existence before uploading, so that the write fails fast.
+	createOnly := opt.HasCreateOnly && opt.CreateOnly
+	if createOnly && !cond.hasIfNoneMatch {
+		cond.hasIfNoneMatch = true
+		cond.ifNoneMatch = "*"
+	}
+	lease, unlock, err := s.lockConditions(ctx, path, cond, opt.HasLeaseID, opt.LeaseID)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	tmp, err := atomicTempPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// The temporary file is new, so the conditions and lease of path don't apply to it.
+	tmpOpt := opt
+	tmpOpt.HasAtomicWrite = false
+	tmpOpt.HasCreateOnly = false
+	tmpOpt.HasIfMatch = false
+	tmpOpt.HasIfNoneMatch = false
+	tmpOpt.HasIfModifiedSince = false
+	tmpOpt.HasIfUnmodifiedSince = false
+	tmpOpt.HasLeaseID = false
+	// The timeout has been applied on the whole atomic write by write.
+	tmpOpt.HasTimeout = false
+
+	n, err = s.write(ctx, tmp, r, size, tmpOpt)
+	if err == nil {
+		// The file created by others while uploading will not be replaced with create_only.
+		options := &file.RenameOptions{
+			ReplaceIfExists: to.Ptr(!createOnly),
+		}
+		if lease != nil {
+			options.DestinationLeaseAccessConditions = &file.DestinationLeaseAccessConditions{
+				DestinationLeaseID: lease.LeaseID,
+			}
+		}
+		// Rename keeps the headers, metadata and SMB properties set while writing.
+		_, err = s.fileClient(tmp).Rename(ctx, s.getAbsFilePath(path), options)
+		if createOnly && fileerror.HasCode(err, fileerror.ResourceAlreadyExists) {
+			err = fmt.Errorf("%w: %s already exists", ErrConditionNotMet, path)
+		}
+	}
+	if err != nil {
+		// The temporary file is removed best effort, the error of write is more important.
+		_, _ = s.fileClient(tmp).Delete(context.Background(), nil)
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,37 @@
+package azfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAtomicTempPath(t *testing.T) {
+	cases := []struct {
+		name string
+		path string
+		dir  string
+	}{
+		{"short name", "abc", ""},
+		{"nested path", "a/b/abc", "a/b/"},
+		{"name of max length", "a/" + strings.Repeat("x", 255), "a/"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp, err := atomicTempPath(tt.path)
+			if err != nil {
+				t.Fatalf("atomicTempPath(%q): %v", tt.path, err)
+			}
+
+			if !strings.HasPrefix(tmp, tt.dir) || strings.Contains(strings.TrimPrefix(tmp, tt.dir), "/") {
+				t.Errorf("atomicTempPath(%q) = %q, expected in dir %q", tt.path, tmp, tt.dir)
+			}
+			if name := strings.TrimPrefix(tmp, tt.dir); len(name) > 255 {
+				t.Errorf("atomicTempPath(%q) = %q, name is %d characters long", tt.path, tmp, len(name))
+			}
+			if !strings.HasSuffix(tmp, atomicTempSuffix) {
+				t.Errorf("atomicTempPath(%q) = %q, expected suffix %q", tt.path, tmp, atomicTempSuffix)
+			}
+		})
+	}
+}
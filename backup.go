@@ -0,0 +1,120 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Backup will copy all files under the work dir into path of dst, as of a new
+// snapshot of the share.
+//
+// This function will create a context by default.
+func (s *Storage) Backup(dst types.Storager, path string, concurrency int, pairs ...types.Pair) (snapshot string, err error) {
+	return s.BackupWithContext(context.Background(), dst, path, concurrency, pairs...)
+}
+
+// BackupWithContext will copy all files under the work dir into path of dst, as of a
+// new snapshot of the share.
+//
+// The snapshot is created first and all files are read from it, so the backup is a
+// consistent point in time even if the share is being written. The snapshot is deleted
+// after all files copied, and its timestamp is returned as the time of backup. It's
+// kept if the backup failed, so that the backup could be retried from the same point
+// by a storager created with share_snapshot.
+//
+// concurrency decides how many files will be copied at the same time, and pairs are
+// passed to Write of dst for every file.
+func (s *Storage) BackupWithContext(ctx context.Context, dst types.Storager, path string, concurrency int, pairs ...types.Pair) (snapshot string, err error) {
+	defer func() {
+		err = s.formatError("backup", err, path)
+	}()
+
+	if s.readOnly {
+		return "", ErrReadOnly
+	}
+
+	if s.snapshot != "" {
+		return "", fmt.Errorf("backup from share snapshot %s", s.snapshot)
+	}
+	err = s.checkPathMapper("backup")
+	if err != nil {
+		return "", err
+	}
+	// The size listed is the size of encrypted content, which is not the size to write.
+	if s.encryptionKey != nil {
+		return "", fmt.Errorf("%w: backup while client-side encryption enabled", services.ErrCapabilityInsufficient)
+	}
+	// So is the size of compressed content.
+	if s.compression != "" {
+		return "", fmt.Errorf("%w: backup while compression enabled", services.ErrCapabilityInsufficient)
+	}
+
+	output, err := s.share.CreateSnapshot(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	snapshot = *output.Snapshot
+
+	store, err := s.withSnapshot(snapshot)
+	if err != nil {
+		return snapshot, err
+	}
+	entries, err := store.syncList(ctx, "")
+	if err != nil {
+		return snapshot, err
+	}
+
+	pool, poolCtx := newWorkerPool(ctx, concurrency)
+	for name, e := range entries {
+		name, e := name, e
+		ok := pool.Go(poolCtx, func() error {
+			pr, pw := io.Pipe()
+			go func() {
+				_, err := store.read(poolCtx, name, pw, pairStorageRead{})
+				pw.CloseWithError(err)
+			}()
+
+			_, err := dst.WriteWithContext(poolCtx, joinPath(path, name), pr, e.size, pairs...)
+			// The reader should be stopped if the write failed.
+			pr.CloseWithError(err)
+			return err
+		})
+		if !ok {
+			break
+		}
+	}
+	err = pool.Wait()
+	if err != nil {
+		return snapshot, err
+	}
+
+	client, err := s.share.WithSnapshot(snapshot)
+	if err != nil {
+		return snapshot, err
+	}
+	_, err = client.Delete(ctx, nil)
+	if err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// withSnapshot will return a copy of the storage which reads from the snapshot.
+func (s *Storage) withSnapshot(snapshot string) (*Storage, error) {
+	share, err := s.share.WithSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	store := *s
+	store.snapshot = snapshot
+	store.share = share
+	store.client = subdirectoryClient(share.NewRootDirectoryClient(), s.workDir)
+	// The cached objects are of the live share.
+	store.statCache = nil
+	return &store, nil
+}
@@ -0,0 +1,96 @@
+package azfile
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimiter limits the bytes transferred per second, all operations of the
+// storage share the same limiter.
+//
+// A nil *rateLimiter is valid and limits nothing.
+type rateLimiter struct {
+	rate float64
+
+	mu sync.Mutex
+	// next is the time when the bytes transferred so far are paid off.
+	next time.Time
+}
+
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	return &rateLimiter{rate: float64(bytesPerSecond)}
+}
+
+// wait will block until the n bytes which have been transferred are allowed by the limit.
+//
+// The bytes are paid after transferred, so the transfer will be paused for a
+// while after a large buffer instead of being split.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type limitReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *rateLimiter
+}
+
+func (r *limitReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if wErr := r.l.wait(r.ctx, n); wErr != nil {
+		return n, wErr
+	}
+	return n, err
+}
+
+type limitWriter struct {
+	ctx context.Context
+	w   io.Writer
+	l   *rateLimiter
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if wErr := w.l.wait(w.ctx, n); wErr != nil && err == nil {
+		return n, wErr
+	}
+	return n, err
+}
+
+// limitReader will limit the rate of reading r if bandwidth_limit is set.
+func (s *Storage) limitReader(ctx context.Context, r io.Reader) io.Reader {
+	if s.limiter == nil {
+		return r
+	}
+	return &limitReader{ctx: ctx, r: r, l: s.limiter}
+}
+
+// limitWriter will limit the rate of writing w if bandwidth_limit is set.
+func (s *Storage) limitWriter(ctx context.Context, w io.Writer) io.Writer {
+	if s.limiter == nil {
+		return w
+	}
+	return &limitWriter{ctx: ctx, w: w, l: s.limiter}
+}
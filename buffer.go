@@ -0,0 +1,88 @@
+package azfile
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+)
+
+// minBufferClass is the size of the smallest pooled buffer.
+const minBufferClass = 4 * 1024
+
+// bufferPool reuses the buffers of ranges, so that transfers don't allocate a
+// new buffer for every range.
+//
+// Buffers are pooled by their size rounded up to the power of 2.
+type bufferPool struct {
+	// pools is a map from the buffer size to *sync.Pool.
+	pools sync.Map
+	// sem limits the buffers borrowed by uploads at the same time, it's nil if not limited.
+	sem chan struct{}
+}
+
+func newBufferPool(limit int) *bufferPool {
+	p := &bufferPool{}
+	if limit > 0 {
+		p.sem = make(chan struct{}, limit)
+	}
+	return p
+}
+
+// get will return a buffer of size which should be returned by put.
+func (p *bufferPool) get(size int64) []byte {
+	class := bufferClass(size)
+	v, ok := p.pools.Load(class)
+	if !ok {
+		v, _ = p.pools.LoadOrStore(class, &sync.Pool{
+			New: func() interface{} {
+				buf := make([]byte, class)
+				return &buf
+			},
+		})
+	}
+	buf := *v.(*sync.Pool).Get().(*[]byte)
+	return buf[:size]
+}
+
+// put will return the buffer got by get, buf must not be used after put.
+func (p *bufferPool) put(buf []byte) {
+	buf = buf[:cap(buf)]
+	v, ok := p.pools.Load(int64(len(buf)))
+	if !ok {
+		return
+	}
+	v.(*sync.Pool).Put(&buf)
+}
+
+// bufferClass will round the size up to the power of 2, so that the last range
+// of files with different sizes could share the buffers.
+func bufferClass(size int64) int64 {
+	if size <= minBufferClass {
+		return minBufferClass
+	}
+	return 1 << bits.Len64(uint64(size-1))
+}
+
+// acquire is the same as get, but blocks while the buffers borrowed have reached
+// the limit, the buffer should be returned by release.
+//
+// Only the buffers borrowed by reader loops which don't wait for other buffers
+// could be acquired, otherwise operations could wait for each other.
+func (p *bufferPool) acquire(ctx context.Context, size int64) ([]byte, error) {
+	if p.sem != nil {
+		select {
+		case p.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.get(size), nil
+}
+
+// release will return the buffer got by acquire.
+func (p *bufferPool) release(buf []byte) {
+	p.put(buf)
+	if p.sem != nil {
+		<-p.sem
+	}
+}
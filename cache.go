@@ -0,0 +1,120 @@
+package azfile
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// maxStatCacheEntries is the number of paths which could be cached before expired entries are swept.
+const maxStatCacheEntries = 10000
+
+// statCache caches the objects returned by stat for a while.
+//
+// A nil *statCache is valid and caches nothing.
+type statCache struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	// entries are keyed by the absolute path, then the object mode hint of stat.
+	entries map[string]map[types.ObjectMode]statCacheEntry
+}
+
+type statCacheEntry struct {
+	o      *types.Object
+	expire time.Time
+}
+
+func newStatCache(ttl time.Duration) *statCache {
+	return &statCache{
+		ttl:     ttl,
+		entries: make(map[string]map[types.ObjectMode]statCacheEntry),
+	}
+}
+
+func (c *statCache) get(path string, mode types.ObjectMode) (*types.Object, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[path][mode]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expire) {
+		delete(c.entries[path], mode)
+		return nil, false
+	}
+	return e.o, true
+}
+
+func (c *statCache) set(path string, mode types.ObjectMode, o *types.Object) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxStatCacheEntries {
+		c.sweep()
+	}
+
+	m, ok := c.entries[path]
+	if !ok {
+		m = make(map[types.ObjectMode]statCacheEntry)
+		c.entries[path] = m
+	}
+	m[mode] = statCacheEntry{o: o, expire: time.Now().Add(c.ttl)}
+}
+
+// invalidate will remove the cached objects of the path and all paths under it.
+func (c *statCache) invalidate(path string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, path)
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for k := range c.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// sweep will remove expired entries, and drop all entries if the cache is still full.
+func (c *statCache) sweep() {
+	now := time.Now()
+	for path, m := range c.entries {
+		for mode, e := range m {
+			if now.After(e.expire) {
+				delete(m, mode)
+			}
+		}
+		if len(m) == 0 {
+			delete(c.entries, path)
+		}
+	}
+
+	if len(c.entries) >= maxStatCacheEntries {
+		c.entries = make(map[string]map[types.ObjectMode]statCacheEntry)
+	}
+}
+
+// InvalidateStatCache will remove the cached stat results of the path and all paths under it.
+//
+// Operations sent by this storage will invalidate the cache automatically, this function
+// is used while the files have been changed by others.
+func (s *Storage) InvalidateStatCache(path string) {
+	s.statCache.invalidate(s.getAbsPath(path))
+}
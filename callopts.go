@@ -0,0 +1,65 @@
+package azfile
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// CallOptions overrides the pipeline options of the service for a single operation,
+// so that one storager could serve different kinds of traffic.
+type CallOptions struct {
+	// Retry replaces the retry options given while creating the service, if not nil.
+	Retry *policy.RetryOptions
+	// Telemetry is prepended to the User-Agent of requests, like "batch-job/1.0".
+	Telemetry string
+	// Header is set to every request, the headers set by SDK will be overwritten.
+	Header http.Header
+}
+
+type callOptionsKey struct{}
+
+// ContextWithCallOptions will carry the call options in ctx, all requests sent by
+// the operation called with the returned context will be applied with them.
+//
+// The call_options pair takes precedence over the options carried by ctx.
+func ContextWithCallOptions(ctx context.Context, co CallOptions) context.Context {
+	if co.Retry != nil {
+		ctx = policy.WithRetryOptions(ctx, *co.Retry)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, co)
+}
+
+// withCallOptions will carry the call options given by pair in ctx.
+func withCallOptions(ctx context.Context, has bool, co CallOptions) context.Context {
+	if !has {
+		return ctx
+	}
+	return ContextWithCallOptions(ctx, co)
+}
+
+// callOptionsPolicy will apply the call options carried by request context.
+//
+// It should be a per call policy which runs after the telemetry policy.
+type callOptionsPolicy struct{}
+
+// Do implements policy.Policy
+func (callOptionsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	if co, ok := raw.Context().Value(callOptionsKey{}).(CallOptions); ok {
+		if co.Telemetry != "" {
+			ua := co.Telemetry
+			if v := raw.Header.Get("User-Agent"); v != "" {
+				ua += " " + v
+			}
+			raw.Header.Set("User-Agent", ua)
+		}
+		for k, v := range co.Header {
+			raw.Header[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+
+	return req.Next()
+}
@@ -0,0 +1,103 @@
+package azfile
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// Checkpoint is the progress of an interrupted upload.
+type Checkpoint struct {
+	// Size is the total size of the content.
+	Size int64
+	// Offset is the size of content which has been uploaded, all ranges before it are completed.
+	Offset int64
+}
+
+// CheckpointStore persists the checkpoints of uploads, so that an interrupted
+// upload could be resumed by another write with the same path and size.
+//
+// The key is composed of the share name and the absolute path of the file.
+type CheckpointStore interface {
+	// Load will return nil without error if the checkpoint doesn't exist.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	Save(ctx context.Context, key string, cp *Checkpoint) error
+	// Delete will be called after the upload completed.
+	Delete(ctx context.Context, key string) error
+}
+
+// checkpointSegmentChunks is the number of chunks per worker uploaded between two checkpoints.
+const checkpointSegmentChunks = 4
+
+func (s *Storage) checkpointKey(path string) string {
+	return s.name + "/" + s.getAbsPath(path)
+}
+
+// loadCheckpoint will return the offset to resume the upload from, 0 means the
+// upload should be started from scratch.
+//
+// The checkpoint is only used if the file still exists with the same size, the
+// content written by others while interrupted could not be detected.
+func (s *Storage) loadCheckpoint(ctx context.Context, store CheckpointStore, path string, size int64) (int64, error) {
+	cp, err := store.Load(ctx, s.checkpointKey(path))
+	if err != nil {
+		return 0, err
+	}
+	if cp == nil || cp.Size != size || cp.Offset <= 0 || cp.Offset > size {
+		return 0, nil
+	}
+
+	output, err := s.fileClient(path).GetProperties(ctx, nil)
+	if err != nil {
+		if checkError(err, fileNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if output.ContentLength == nil || *output.ContentLength != size {
+		return 0, nil
+	}
+	return cp.Offset, nil
+}
+
+// uploadRangesWithCheckpoint will upload the content from offset like uploadRanges,
+// and save a checkpoint after every segment completed.
+//
+// r must be positioned at offset, and the checkpoint should be deleted by caller
+// after the write completed.
+func (s *Storage) uploadRangesWithCheckpoint(ctx context.Context, store CheckpointStore, path string, client *file.Client,
+	offset int64, r io.Reader, size int64, chunkSize int64, concurrency int, useCRC64 bool, lease *file.LeaseAccessConditions) error {
+	key := s.checkpointKey(path)
+	segment := chunkSize * int64(concurrency) * checkpointSegmentChunks
+
+	for offset < size {
+		n := segment
+		if offset+n > size {
+			n = size - offset
+		}
+
+		err := s.uploadRanges(ctx, client, offset, r, n, chunkSize, concurrency, useCRC64, lease)
+		if err != nil {
+			return err
+		}
+		offset += n
+
+		err = store.Save(ctx, key, &Checkpoint{Size: size, Offset: offset})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipReader will skip the first n bytes of r.
+func skipReader(r io.Reader, n int64) error {
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(n, io.SeekCurrent)
+		return err
+	}
+
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
@@ -0,0 +1,142 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// CleanupPolicy decides which files are expired by Cleanup.
+//
+// Azure Files has no lifecycle management like blob, so the expiry is decided by the
+// last modified time listed and applied on the client side.
+type CleanupPolicy struct {
+	// Prefixes are listed in prefix mode relative to the work dir, empty means all files.
+	Prefixes []string
+	// MaxAge is the age of last modified time after which files are expired.
+	MaxAge time.Duration
+	// ArchivePrefix will move the expired files under it with the same path instead of
+	// deleting them. Files already under it are never expired again.
+	ArchivePrefix string
+}
+
+// Cleanup will delete or archive all files expired by policy.
+//
+// This function will create a context by default.
+func (s *Storage) Cleanup(policy CleanupPolicy, pairs ...types.Pair) (expired []*types.Object, err error) {
+	return s.CleanupWithContext(context.Background(), policy, pairs...)
+}
+
+// CleanupWithContext will delete or archive all files expired by policy.
+//
+// All files under the prefixes are listed before any of them deleted, and the expired
+// files are returned. With dry_run, they are reported and returned but not touched, so
+// that the policy could be checked first.
+//
+// The pairs of Delete are supported and applied to every file, concurrency decides how
+// many files will be deleted or moved at the same time, and progress reports the number
+// and bytes of files which have been cleaned up.
+func (s *Storage) CleanupWithContext(ctx context.Context, policy CleanupPolicy, pairs ...types.Pair) (expired []*types.Object, err error) {
+	defer func() {
+		err = s.formatError("cleanup", err)
+	}()
+
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if policy.MaxAge <= 0 {
+		return nil, fmt.Errorf("cleanup with max age %s", policy.MaxAge)
+	}
+
+	pairs = append(pairs, s.defaultPairs.Delete...)
+	opt, err := s.parsePairStorageDelete(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := policy.ArchivePrefix
+	if archive != "" && !strings.HasSuffix(archive, "/") {
+		archive += "/"
+	}
+
+	listOpt := pairStorageList{
+		HasListMode:           true,
+		ListMode:              types.ListModePrefix,
+		HasListModifiedBefore: true,
+		ListModifiedBefore:    time.Now().Add(-policy.MaxAge),
+	}
+	prefixes := policy.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	// Overlapped prefixes could list the same file more than once.
+	seen := make(map[string]bool)
+	for _, prefix := range prefixes {
+		it, err := s.list(ctx, prefix, listOpt)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			o, err := it.Next()
+			if errors.Is(err, types.IterateDone) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if seen[o.Path] || archive != "" && strings.HasPrefix(o.Path, archive) {
+				continue
+			}
+			seen[o.Path] = true
+			expired = append(expired, o)
+		}
+	}
+
+	var mu sync.Mutex
+	var total Progress
+	done := func(o *types.Object) {
+		if !opt.HasProgress {
+			return
+		}
+		size, _ := o.GetContentLength()
+
+		mu.Lock()
+		defer mu.Unlock()
+		total.Objects++
+		total.Bytes += size
+		opt.Progress(total)
+	}
+
+	fileOpt := opt
+	fileOpt.HasObjectMode = true
+	fileOpt.ObjectMode = types.ModeRead
+	// Every file reports progress by done instead.
+	fileOpt.HasProgress = false
+
+	pool, poolCtx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+	for _, o := range expired {
+		o := o
+		ok := pool.Go(poolCtx, func() error {
+			var err error
+			if archive != "" {
+				err = s.move(poolCtx, o.Path, archive+o.Path, pairStorageMove{})
+			} else {
+				err = s.delete(poolCtx, o.Path, fileOpt)
+			}
+			if err != nil {
+				return err
+			}
+			done(o)
+			return nil
+		})
+		if !ok {
+			break
+		}
+	}
+	return expired, pool.Wait()
+}
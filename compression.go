@@ -0,0 +1,133 @@
+package azfile
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+)
+
+// CompressionGzip is the only compression supported, zstd is not decoded by browsers
+// and other clients of the share yet.
+const CompressionGzip = "gzip"
+
+// parseCompression will validate the compression pairs of storage.
+func parseCompression(opt pairStorageNew) (compression string, threshold int64, err error) {
+	if !opt.HasCompression {
+		return "", 0, nil
+	}
+	if opt.Compression != CompressionGzip {
+		return "", 0, fmt.Errorf("compression %s is not supported", opt.Compression)
+	}
+	// The size of compressed content is unknown before compressed, which could not be
+	// encrypted by segments.
+	if opt.HasEncryptionKey {
+		return "", 0, fmt.Errorf("compression with client-side encryption")
+	}
+	if opt.HasCompressionThreshold {
+		if opt.CompressionThreshold < 0 {
+			return "", 0, fmt.Errorf("compression threshold %d is invalid", opt.CompressionThreshold)
+		}
+		threshold = opt.CompressionThreshold
+	}
+	return opt.Compression, threshold, nil
+}
+
+// shouldCompress will check whether the content of size should be compressed while written.
+//
+// The content with content_encoding is encoded by caller already, and content_md5 is
+// the MD5 of the content before compressed, so they are written as is.
+func (s *Storage) shouldCompress(size int64, opt pairStorageWrite) bool {
+	if s.compression == "" || opt.HasOffset || opt.HasContentEncoding || opt.HasContentMd5 {
+		return false
+	}
+	return size < 0 || size >= s.compressionThreshold
+}
+
+// countReader counts the bytes read from r.
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// newCompressReader will return the compressed content of r, which is compressed in
+// another goroutine. The reader must be closed to stop the goroutine, r is not read
+// anymore after Close returned.
+func newCompressReader(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, r)
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return &compressReader{PipeReader: pr, done: done}
+}
+
+// compressReader waits for the goroutine compressing while closed.
+type compressReader struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close will fail the writes of the goroutine, and wait until it exits.
+func (r *compressReader) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+	return err
+}
+
+// readCompressed will decompress the file from the beginning, then write the content
+// from offset to w. A count of 0 means till the end of content.
+//
+// The content could not be decompressed from the middle, so the content before offset
+// is downloaded and discarded.
+func (s *Storage) readCompressed(ctx context.Context, path string, w io.Writer, offset, count int64, opt pairStorageRead) (n int64, err error) {
+	rs, err := s.newReadStream(ctx, path, pairStorageRead{
+		HasReadRetries: opt.HasReadRetries,
+		ReadRetries:    opt.ReadRetries,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		cErr := rs.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+
+	zr, err := gzip.NewReader(rs)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		_, err = io.CopyN(io.Discard, zr, offset)
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if count > 0 {
+		n, err = io.CopyN(w, zr, count)
+		if err == io.EOF {
+			err = nil
+		}
+		return n, err
+	}
+	return io.Copy(w, zr)
+}
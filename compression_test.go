@@ -0,0 +1,21 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+func TestWriteWithOffsetWhileCompressionEnabled(t *testing.T) {
+	s := newTestStorage(t, "/", WithCompression(CompressionGzip))
+
+	// The write is rejected before any request sent.
+	_, err := s.WriteWithContext(context.Background(), "abc", strings.NewReader("content"), 7, ps.WithOffset(10))
+	if !errors.Is(err, services.ErrCapabilityInsufficient) {
+		t.Errorf("write with offset while compression enabled = %v, expected %v", err, services.ErrCapabilityInsufficient)
+	}
+}
@@ -0,0 +1,110 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// conditions are the preconditions of an operation.
+//
+// File service doesn't support conditional headers, so the conditions will be
+// checked against the properties of the file before the operation.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/specifying-conditional-headers-for-blob-service-operations
+type conditions struct {
+	hasIfMatch           bool
+	ifMatch              string
+	hasIfNoneMatch       bool
+	ifNoneMatch          string
+	hasIfModifiedSince   bool
+	ifModifiedSince      time.Time
+	hasIfUnmodifiedSince bool
+	ifUnmodifiedSince    time.Time
+
+	// lock will make lockConditions hold a lease even if there is no condition.
+	lock bool
+}
+
+func (c conditions) isEmpty() bool {
+	return !c.hasIfMatch && !c.hasIfNoneMatch && !c.hasIfModifiedSince && !c.hasIfUnmodifiedSince
+}
+
+// check will check the conditions against the etag and last modified time of the file.
+func (c conditions) check(exists bool, etag string, lastModified time.Time) error {
+	etag = strings.Trim(etag, `"`)
+
+	if c.hasIfMatch {
+		if !exists || (c.ifMatch != "*" && strings.Trim(c.ifMatch, `"`) != etag) {
+			return fmt.Errorf("%w: if-match %s", ErrConditionNotMet, c.ifMatch)
+		}
+	}
+	if c.hasIfNoneMatch && exists {
+		if c.ifNoneMatch == "*" || strings.Trim(c.ifNoneMatch, `"`) == etag {
+			return fmt.Errorf("%w: if-none-match %s", ErrConditionNotMet, c.ifNoneMatch)
+		}
+	}
+	if c.hasIfModifiedSince {
+		if !exists || !lastModified.After(c.ifModifiedSince) {
+			return fmt.Errorf("%w: if-modified-since %s", ErrConditionNotMet, c.ifModifiedSince.Format(time.RFC1123))
+		}
+	}
+	// A file that doesn't exist has never been modified.
+	if c.hasIfUnmodifiedSince && exists {
+		if lastModified.After(c.ifUnmodifiedSince) {
+			return fmt.Errorf("%w: if-unmodified-since %s", ErrConditionNotMet, c.ifUnmodifiedSince.Format(time.RFC1123))
+		}
+	}
+	return nil
+}
+
+// checkConditions will check the conditions against the current properties of the file.
+func (s *Storage) checkConditions(ctx context.Context, path string, c conditions) error {
+	if c.isEmpty() {
+		return nil
+	}
+
+	output, err := s.fileClient(path).GetProperties(ctx, nil)
+	if err != nil {
+		if checkError(err, fileNotFound) {
+			return c.check(false, "", time.Time{})
+		}
+		return err
+	}
+
+	return c.check(true, string(deref(output.ETag)), deref(output.LastModified))
+}
+
+// lockConditions will check the conditions while holding a lease on the file, so that
+// the file could not be modified by others between the check and the operation.
+//
+// The returned lease should be used by the operation, and unlock must be called after it.
+// If the caller has provided a lease, it will be used as is.
+func (s *Storage) lockConditions(ctx context.Context, path string, c conditions, hasLeaseID bool, leaseID string) (
+	lac *file.LeaseAccessConditions, unlock func(), err error) {
+	unlock = func() {}
+
+	if (c.isEmpty() && !c.lock) || hasLeaseID {
+		return formatLeaseAccessConditions(hasLeaseID, leaseID), unlock, s.checkConditions(ctx, path, c)
+	}
+
+	lac, unlock, err = lockFile(ctx, s.fileClient(path))
+	if err != nil {
+		// The file doesn't exist, there is nothing to lock.
+		if checkError(err, fileNotFound) {
+			return nil, unlock, c.check(false, "", time.Time{})
+		}
+		return nil, unlock, err
+	}
+
+	err = s.checkConditions(ctx, path, c)
+	if err != nil {
+		unlock()
+		return nil, func() {}, err
+	}
+
+	return lac, unlock, nil
+}
@@ -0,0 +1,164 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// Status of server-side copy.
+const (
+	CopyStatusPending = string(file.CopyStatusTypePending)
+	CopyStatusSuccess = string(file.CopyStatusTypeSuccess)
+	CopyStatusAborted = string(file.CopyStatusTypeAborted)
+	CopyStatusFailed  = string(file.CopyStatusTypeFailed)
+)
+
+// CopyStatus is the status of the last server-side copy whose destination is the file.
+type CopyStatus struct {
+	// ID is the copy id, which is required to abort the copy.
+	ID     string
+	Status string
+	// Description carries the reason of the failed or aborted copy.
+	Description string
+
+	// CopiedBytes and TotalBytes will be 0 if the progress is not reported by service.
+	CopiedBytes int64
+	TotalBytes  int64
+}
+
+// CopyProgressFunc will be called every time the status of a pending copy is polled.
+type CopyProgressFunc func(status CopyStatus)
+
+// GetCopyStatus will get the status of the last server-side copy whose destination is path.
+//
+// This function will create a context by default.
+func (s *Storage) GetCopyStatus(path string) (status CopyStatus, err error) {
+	return s.GetCopyStatusWithContext(context.Background(), path)
+}
+
+// GetCopyStatusWithContext will get the status of the last server-side copy whose destination is path.
+func (s *Storage) GetCopyStatusWithContext(ctx context.Context, path string) (status CopyStatus, err error) {
+	defer func() {
+		err = s.formatError("get_copy_status", err, path)
+	}()
+
+	output, err := s.fileClient(path).GetProperties(ctx, nil)
+	if err != nil {
+		return CopyStatus{}, err
+	}
+
+	return parseCopyStatus(output.CopyID, output.CopyStatus, output.CopyStatusDescription, output.CopyProgress)
+}
+
+// WaitForCopy will wait until the server-side copy whose destination is path finished.
+//
+// This function will create a context by default.
+func (s *Storage) WaitForCopy(path string, pollInterval time.Duration) (status CopyStatus, err error) {
+	return s.WaitForCopyWithContext(context.Background(), path, pollInterval)
+}
+
+// WaitForCopyWithContext will poll the copy status every pollInterval until the
+// server-side copy whose destination is path is not pending anymore.
+//
+// The last status will be returned along with ErrCopyFailed if the copy failed
+// or has been aborted. The copy will keep running if ctx is canceled, use AbortCopy
+// to abort it. The default poll interval will be used if pollInterval is not positive.
+func (s *Storage) WaitForCopyWithContext(ctx context.Context, path string, pollInterval time.Duration) (status CopyStatus, err error) {
+	defer func() {
+		err = s.formatError("wait_for_copy", err, path)
+	}()
+
+	if pollInterval <= 0 {
+		pollInterval = copyPollInterval
+	}
+
+	client := s.fileClient(path)
+	for {
+		output, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			return CopyStatus{}, err
+		}
+
+		status, err = parseCopyStatus(output.CopyID, output.CopyStatus, output.CopyStatusDescription, output.CopyProgress)
+		if err != nil {
+			return status, err
+		}
+
+		switch status.Status {
+		case CopyStatusPending:
+		case CopyStatusSuccess:
+			return status, nil
+		case "":
+			// The file has never been the destination of a copy, there is nothing to wait.
+			return status, nil
+		default:
+			return status, fmt.Errorf("%w: %s, %s", ErrCopyFailed, status.Status, status.Description)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// AbortCopy will abort the pending server-side copy, and leave a zero-length destination file with full metadata.
+//
+// This function will create a context by default.
+func (s *Storage) AbortCopy(path string, copyID string) (err error) {
+	return s.AbortCopyWithContext(context.Background(), path, copyID)
+}
+
+// AbortCopyWithContext will abort the pending server-side copy, and leave a zero-length destination file with full metadata.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/abort-copy-file
+func (s *Storage) AbortCopyWithContext(ctx context.Context, path string, copyID string) (err error) {
+	defer func() {
+		err = s.formatError("abort_copy", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err = s.fileClient(path).AbortCopy(ctx, copyID, nil)
+	return err
+}
+
+// parseCopyStatus will parse the copy properties returned by service.
+//
+// The progress is in the format of "<copied bytes>/<total bytes>".
+func parseCopyStatus(id *string, status *file.CopyStatusType, desc *string, progress *string) (cs CopyStatus, err error) {
+	if id != nil {
+		cs.ID = *id
+	}
+	if status != nil {
+		cs.Status = string(*status)
+	}
+	if desc != nil {
+		cs.Description = *desc
+	}
+	if progress == nil || *progress == "" {
+		return cs, nil
+	}
+
+	copied, total, ok := strings.Cut(*progress, "/")
+	if !ok {
+		return cs, fmt.Errorf("copy progress %q is invalid", *progress)
+	}
+	cs.CopiedBytes, err = strconv.ParseInt(copied, 10, 64)
+	if err != nil {
+		return cs, fmt.Errorf("copy progress %q is invalid: %w", *progress, err)
+	}
+	cs.TotalBytes, err = strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return cs, fmt.Errorf("copy progress %q is invalid: %w", *progress, err)
+	}
+	return cs, nil
+}
@@ -0,0 +1,123 @@
+package azfile
+
+import (
+	"context"
+	pathpkg "path"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// CopyDir will copy all files under src into dst with server-side copy, the
+// structure of directories will be kept.
+//
+// The pairs of Copy are supported, and concurrency decides how many files will
+// be copied at the same time. The progress pair reports the accumulated progress
+// after every file copied.
+//
+// This function will create a context by default.
+func (s *Storage) CopyDir(src, dst string, pairs ...types.Pair) (err error) {
+	return s.CopyDirWithContext(context.Background(), src, dst, pairs...)
+}
+
+// CopyDirWithContext will copy all files under src into dst with server-side copy, the
+// structure of directories will be kept.
+func (s *Storage) CopyDirWithContext(ctx context.Context, src, dst string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("copy_dir", err, src, dst)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	err = s.checkPathMapper("copy dir")
+	if err != nil {
+		return err
+	}
+
+	pairs = append(pairs, s.defaultPairs.Copy...)
+	opt, err := s.parsePairStorageCopy(pairs)
+	if err != nil {
+		return err
+	}
+
+	srcDir := s.dirClient(src)
+	if opt.HasSourceShare {
+		srcDir = subdirectoryClient(s.service.NewShareClient(opt.SourceShare).NewRootDirectoryClient(), src)
+	}
+
+	// Every file reports its own progress, we accumulate them into the progress of the whole directory.
+	if opt.HasProgress {
+		var mu sync.Mutex
+		var total Progress
+		fn := opt.Progress
+		opt.Progress = func(p Progress) {
+			if p.Objects == 0 {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			total.Objects += p.Objects
+			total.Bytes += p.Bytes
+			fn(total)
+		}
+	}
+
+	pool, ctx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+
+	// Directories are walked in depth-first order, the paths are relative to src.
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		rel := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+
+		_, err = s.createDir(ctx, joinPath(dst, rel), pairStorageCreateDir{})
+		if err != nil {
+			pool.setError(err)
+			break
+		}
+
+		pager := subdirectoryClient(srcDir, rel).NewListFilesAndDirectoriesPager(&directory.ListFilesAndDirectoriesOptions{
+			MaxResults: to.Ptr(int32(maxListPageSize)),
+		})
+		for pager.More() {
+			output, err := pager.NextPage(ctx)
+			if err != nil {
+				pool.setError(err)
+				return pool.Wait()
+			}
+
+			for _, v := range output.Segment.Directories {
+				dirs = append(dirs, joinPath(rel, *v.Name))
+			}
+			for _, v := range output.Segment.Files {
+				name := joinPath(rel, *v.Name)
+				ok := pool.Go(ctx, func() error {
+					return s.copy(ctx, joinPath(src, name), joinPath(dst, name), opt)
+				})
+				if !ok {
+					return pool.Wait()
+				}
+			}
+		}
+	}
+
+	return pool.Wait()
+}
+
+// joinPath will join the paths, and keep the leading "/" of the first one.
+func joinPath(base, rel string) string {
+	if rel == "" {
+		return base
+	}
+	if base == "" {
+		return rel
+	}
+	return strings.TrimSuffix(base, "/") + "/" + pathpkg.Clean(rel)
+}
@@ -0,0 +1,65 @@
+package azfile
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc64"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// crc64Table is the table of the CRC64 polynomial used by Azure Storage.
+var crc64Table = crc64.MakeTable(0x9A6C9329AC4BC9B5)
+
+const headerContentCRC64 = "x-ms-content-crc64"
+
+type contentCRC64Key struct{}
+
+// withContentCRC64 will ask contentCRC64Policy to send the CRC64 of data along
+// with the request, and verify the CRC64 returned by service.
+func withContentCRC64(ctx context.Context, data []byte) context.Context {
+	return withContentCRC64Sum(ctx, crc64.Checksum(data, crc64Table))
+}
+
+// withContentCRC64Sum is the same as withContentCRC64, but takes the computed CRC64.
+func withContentCRC64Sum(ctx context.Context, sum uint64) context.Context {
+	return context.WithValue(ctx, contentCRC64Key{}, formatContentCRC64(sum))
+}
+
+// formatContentCRC64 will encode the CRC64 in little-endian base64 as the service expects.
+func formatContentCRC64(sum uint64) string {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], sum)
+	return base64.StdEncoding.EncodeToString(buf[:])
+}
+
+// contentCRC64Policy will set x-ms-content-crc64 for requests whose context
+// carries the CRC64 set by withContentCRC64.
+//
+// The SDK only supports transactional MD5 for files, so we set the header by ourselves.
+type contentCRC64Policy struct{}
+
+// Do implements policy.Policy
+func (contentCRC64Policy) Do(req *policy.Request) (*http.Response, error) {
+	sum, ok := req.Raw().Context().Value(contentCRC64Key{}).(string)
+	if !ok {
+		return req.Next()
+	}
+
+	req.Raw().Header.Set(headerContentCRC64, sum)
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+
+	// The service will echo the CRC64 it computed, the header could be absent
+	// while talking to emulators or old api versions.
+	if v := resp.Header.Get(headerContentCRC64); v != "" && v != sum {
+		return resp, fmt.Errorf("%w: expected %s, got %s", ErrContentCRC64Mismatch, sum, v)
+	}
+	return resp, nil
+}
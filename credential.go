@@ -0,0 +1,77 @@
+package azfile
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+)
+
+// TokenCredential is the credential which could acquire OAuth token from Azure AD,
+// for example, the credentials provided by azidentity.
+type TokenCredential = azcore.TokenCredential
+
+// parseConnectionString will parse the Azure storage connection string into
+// the url of file service and the shared key credential.
+//
+// The SAS token will be set into the query of the url, and the credential will be nil.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/common/storage-configure-connection-string
+func parseConnectionString(cs string) (u *url.URL, cred *service.SharedKeyCredential, err error) {
+	kv := make(map[string]string)
+	for _, v := range strings.Split(cs, ";") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		// The value could contain "=", like the signature in SAS token.
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("connection string segment %q is invalid", v)
+		}
+		kv[parts[0]] = parts[1]
+	}
+
+	accountName := kv["AccountName"]
+
+	if ep, ok := kv["FileEndpoint"]; ok {
+		u, err = url.Parse(ep)
+	} else {
+		if accountName == "" {
+			return nil, nil, fmt.Errorf("connection string must contain AccountName or FileEndpoint")
+		}
+
+		protocol := kv["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := kv["EndpointSuffix"]
+		if suffix == "" {
+			suffix = defaultEndpointSuffix
+		}
+
+		u, err = formatEndpoint(protocol, accountName, suffix)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sas, ok := kv["SharedAccessSignature"]; ok {
+		u.RawQuery = strings.TrimPrefix(sas, "?")
+		return u, nil, nil
+	}
+	if key, ok := kv["AccountKey"]; ok {
+		cred, err = service.NewSharedKeyCredential(accountName, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		return u, cred, nil
+	}
+
+	// Connection string without credential is allowed, credential could be
+	// provided by other pairs.
+	return u, nil, nil
+}
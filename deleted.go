@@ -0,0 +1,95 @@
+package azfile
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// DeletedShare is a soft-deleted share which could be restored.
+type DeletedShare struct {
+	Name string
+	// Version is required to restore the share, a share could be deleted multiple times
+	// with different versions.
+	Version string
+
+	DeletedTime            time.Time
+	RemainingRetentionDays int32
+}
+
+// ListDeletedShares will list all soft-deleted shares.
+//
+// This function will create a context by default.
+func (s *Service) ListDeletedShares() (shares []DeletedShare, err error) {
+	return s.ListDeletedSharesWithContext(context.Background())
+}
+
+// ListDeletedSharesWithContext will list all soft-deleted shares.
+//
+// Shares will be listed only if share soft delete is enabled on the account.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/files/storage-files-enable-soft-delete
+func (s *Service) ListDeletedSharesWithContext(ctx context.Context) (shares []DeletedShare, err error) {
+	defer func() {
+		err = s.formatError("list_deleted_shares", err, "")
+	}()
+
+	pager := s.service.NewListSharesPager(&service.ListSharesOptions{
+		Include: service.ListSharesInclude{Deleted: true},
+	})
+	for pager.More() {
+		output, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.Shares {
+			if v.Deleted == nil || !*v.Deleted {
+				continue
+			}
+
+			share := DeletedShare{Name: *v.Name}
+			if v.Version != nil {
+				share.Version = *v.Version
+			}
+			if v.Properties != nil {
+				if v.Properties.DeletedTime != nil {
+					share.DeletedTime = *v.Properties.DeletedTime
+				}
+				if v.Properties.RemainingRetentionDays != nil {
+					share.RemainingRetentionDays = *v.Properties.RemainingRetentionDays
+				}
+			}
+			shares = append(shares, share)
+		}
+	}
+
+	return shares, nil
+}
+
+// RestoreShare will restore the soft-deleted share and return the storager of it.
+//
+// This function will create a context by default.
+func (s *Service) RestoreShare(name, version string) (store types.Storager, err error) {
+	return s.RestoreShareWithContext(context.Background(), name, version)
+}
+
+// RestoreShareWithContext will restore the soft-deleted share and return the storager of it.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/restore-share
+func (s *Service) RestoreShareWithContext(ctx context.Context, name, version string) (store types.Storager, err error) {
+	defer func() {
+		err = s.formatError("restore_share", err, name)
+	}()
+
+	_, err = s.service.RestoreShare(ctx, name, version, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newStorage(ps.WithName(name))
+}
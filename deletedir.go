@@ -0,0 +1,105 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// DeleteDir will delete the directory along with all files and directories under it.
+//
+// This function will create a context by default.
+func (s *Storage) DeleteDir(path string, pairs ...types.Pair) (err error) {
+	return s.DeleteDirWithContext(context.Background(), path, pairs...)
+}
+
+// DeleteDirWithContext will delete the directory along with all files and directories under it.
+//
+// The pairs of Delete are supported and applied to every file, and concurrency decides
+// how many files will be deleted at the same time. With lease_files, every file is leased
+// before deleted, so that the files being written by others will fail the delete instead
+// of being removed in the middle. Directories are deleted after all files under them.
+func (s *Storage) DeleteDirWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("delete_dir", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	err = s.checkPathMapper("delete dir")
+	if err != nil {
+		return err
+	}
+
+	pairs = append(pairs, s.defaultPairs.Delete...)
+	opt, err := s.parsePairStorageDelete(pairs)
+	if err != nil {
+		return err
+	}
+
+	fileOpt := opt
+	fileOpt.HasObjectMode = true
+	fileOpt.ObjectMode = types.ModeRead
+
+	// The context of pool will be canceled after waited, so the directories are deleted with ctx.
+	pool, poolCtx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+
+	// Directories are walked in depth-first order, the paths are relative to path.
+	// Every directory is walked after its parent, so they are deleted in reverse order.
+	var walked []string
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		rel := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+		walked = append(walked, rel)
+
+		pager := s.dirClient(joinPath(path, rel)).NewListFilesAndDirectoriesPager(&directory.ListFilesAndDirectoriesOptions{
+			MaxResults: to.Ptr(int32(maxListPageSize)),
+		})
+		for pager.More() {
+			output, err := pager.NextPage(poolCtx)
+			if err != nil {
+				// The directory has been deleted, just like Delete.
+				if rel == "" && checkError(err, fileNotFound) {
+					return pool.Wait()
+				}
+				pool.setError(err)
+				return pool.Wait()
+			}
+
+			for _, v := range output.Segment.Directories {
+				dirs = append(dirs, joinPath(rel, *v.Name))
+			}
+			for _, v := range output.Segment.Files {
+				name := joinPath(path, joinPath(rel, *v.Name))
+				ok := pool.Go(poolCtx, func() error {
+					return s.delete(poolCtx, name, fileOpt)
+				})
+				if !ok {
+					return pool.Wait()
+				}
+			}
+		}
+	}
+
+	err = pool.Wait()
+	if err != nil {
+		return err
+	}
+
+	dirOpt := opt
+	dirOpt.HasObjectMode = true
+	dirOpt.ObjectMode = types.ModeDir
+	for i := len(walked) - 1; i >= 0; i-- {
+		err = s.delete(ctx, joinPath(path, walked[i]), dirOpt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
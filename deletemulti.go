@@ -0,0 +1,74 @@
+package azfile
+
+import (
+	"context"
+	"sync"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// DeleteMulti will delete all paths with at most concurrency deletes at the same time.
+//
+// This function will create a context by default.
+func (s *Storage) DeleteMulti(paths []string, concurrency int, pairs ...types.Pair) (errs []error, err error) {
+	return s.DeleteMultiWithContext(context.Background(), paths, concurrency, pairs...)
+}
+
+// DeleteMultiWithContext will delete all paths with at most concurrency deletes at the same time.
+//
+// The pairs of Delete are supported and applied to every path. The error of every path
+// is returned in errs at the same index, a failed path doesn't stop the others, and the
+// paths which don't exist are deleted without error like Delete. err is only returned
+// if the pairs are invalid.
+//
+// The progress pair reports the accumulated number of deleted objects.
+func (s *Storage) DeleteMultiWithContext(ctx context.Context, paths []string, concurrency int, pairs ...types.Pair) (errs []error, err error) {
+	defer func() {
+		err = s.formatError("delete_multi", err)
+	}()
+
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	pairs = append(pairs, s.defaultPairs.Delete...)
+	opt, err := s.parsePairStorageDelete(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every delete reports its own progress, we accumulate them into the progress of all paths.
+	if opt.HasProgress {
+		var mu sync.Mutex
+		var total Progress
+		fn := opt.Progress
+		opt.Progress = func(p Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			total.Objects += p.Objects
+			fn(total)
+		}
+	}
+
+	errs = make([]error, len(paths))
+
+	// Tasks never fail, so that the pool will not be canceled by a failed path.
+	pool, ctx := newWorkerPool(ctx, concurrency)
+	for i, path := range paths {
+		i, path := i, path
+		ok := pool.Go(ctx, func() error {
+			errs[i] = s.formatError("delete", s.delete(ctx, path, opt), path)
+			return nil
+		})
+		if !ok {
+			// The context has been canceled, the rest paths are not deleted.
+			for j := i; j < len(paths); j++ {
+				errs[j] = s.formatError("delete", ctx.Err(), paths[j])
+			}
+			break
+		}
+	}
+	_ = pool.Wait()
+
+	return errs, nil
+}
@@ -0,0 +1,140 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// defaultReadRetries is the number of times a range or stream will be requested again
+// after its body failed to be read.
+//
+// The requests are retried by pipeline, but the errors while reading body are not.
+const defaultReadRetries = 3
+
+func parseReadRetries(has bool, v int) int {
+	if !has {
+		return defaultReadRetries
+	}
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// ReadIntoWriterAt will download the file into w concurrently.
+//
+// This function will create a context by default.
+func (s *Storage) ReadIntoWriterAt(path string, w io.WriterAt, pairs ...types.Pair) (n int64, err error) {
+	return s.ReadIntoWriterAtWithContext(context.Background(), path, w, pairs...)
+}
+
+// ReadIntoWriterAtWithContext will split the file into ranges, download them concurrently,
+// and write every range into w at its position relative to the offset, like *os.File
+// opened for writing.
+//
+// The pairs of Read are supported, and concurrency decides how many ranges will be
+// downloaded at the same time. Every range will be requested again from where its body
+// failed to be read, up to read_retries times.
+// io_callback will be called with the ranges in the order they are written, which is not
+// the order in the file.
+func (s *Storage) ReadIntoWriterAtWithContext(ctx context.Context, path string, w io.WriterAt, pairs ...types.Pair) (n int64, err error) {
+	defer func() {
+		err = s.formatError("read_into_writer_at", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Read...)
+	opt, err := s.parsePairStorageRead(pairs)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+	defer cancel()
+
+	// Every segment of encrypted file must be opened in order.
+	if s.encryptionKey != nil {
+		return 0, fmt.Errorf("%w: read into writer at while client-side encryption enabled", services.ErrCapabilityInsufficient)
+	}
+
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+	}
+	err = s.checkConditions(ctx, path, cond)
+	if err != nil {
+		return 0, err
+	}
+
+	client := s.fileClient(path)
+
+	var offset, count int64
+	if opt.HasOffset {
+		offset = opt.Offset
+	}
+	if opt.HasSize {
+		count = opt.Size
+	} else {
+		fi, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		count = *fi.ContentLength - offset
+	}
+	if count <= 0 {
+		return 0, nil
+	}
+
+	verify := opt.HasVerifyContentMd5 && opt.VerifyContentMd5
+	retries := parseReadRetries(opt.HasReadRetries, opt.ReadRetries)
+	rangeSize, err := parseChunkSize(opt.HasChunkSize, opt.ChunkSize)
+	if err != nil {
+		return 0, err
+	}
+
+	pool, ctx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+	for start := int64(0); start < count; start += rangeSize {
+		start, size := start, rangeSize
+		if start+size > count {
+			size = count - start
+		}
+
+		ok := pool.Go(ctx, func() error {
+			data, err := s.downloadRange(ctx, client, offset+start, size, verify, retries)
+			if err != nil {
+				return err
+			}
+
+			defer s.buffers.put(data)
+
+			_, err = w.WriteAt(data, start)
+			if err != nil {
+				return err
+			}
+			if opt.HasIoCallback {
+				opt.IoCallback(data)
+			}
+			return s.limiter.wait(ctx, len(data))
+		})
+		if !ok {
+			break
+		}
+	}
+
+	err = pool.Wait()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,286 @@
+package azfile
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const (
+	// downloadManifestName is the file in the local directory which records the
+	// files downloaded by DownloadDir.
+	downloadManifestName = ".azfile-download"
+	// downloadPartSuffix is the suffix of the files being downloaded.
+	downloadPartSuffix = ".azfile-part"
+)
+
+// DownloadDir will download all files under src into the local directory, the
+// structure of directories will be kept.
+//
+// This function will create a context by default.
+func (s *Storage) DownloadDir(src, local string, pairs ...types.Pair) (err error) {
+	return s.DownloadDirWithContext(context.Background(), src, local, pairs...)
+}
+
+// DownloadDirWithContext will download all files under src into the local directory, the
+// structure of directories will be kept.
+//
+// The pairs of Read are supported and applied to every file. concurrency decides how
+// many files will be downloaded at the same time, as well as how many ranges of every file.
+//
+// The ETag and size of every downloaded file are recorded in a manifest in the local
+// directory, so that the files which are not changed since last download will be skipped.
+// A file is downloaded into a part file named with its ETag, and renamed after completed,
+// so that an interrupted download will be resumed from the end of the part file if the
+// remote file is not changed.
+func (s *Storage) DownloadDirWithContext(ctx context.Context, src, local string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("download_dir", err, src)
+	}()
+
+	err = s.checkPathMapper("download dir")
+	if err != nil {
+		return err
+	}
+
+	pairs = append(pairs, s.defaultPairs.Read...)
+	opt, err := s.parsePairStorageRead(pairs)
+	if err != nil {
+		return err
+	}
+	if opt.HasOffset || opt.HasSize {
+		return fmt.Errorf("download dir with offset or size")
+	}
+	// The size listed is the size of compressed content, which could not be compared
+	// with the part files.
+	if s.compression != "" {
+		return fmt.Errorf("%w: download dir while compression enabled", services.ErrCapabilityInsufficient)
+	}
+
+	err = os.MkdirAll(local, 0o755)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := openDownloadManifest(filepath.Join(local, downloadManifestName))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := manifest.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	pool, ctx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+
+	// Directories are walked in depth-first order, the paths are relative to src.
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		rel := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+
+		err = os.MkdirAll(filepath.Join(local, filepath.FromSlash(rel)), 0o755)
+		if err != nil {
+			pool.setError(err)
+			break
+		}
+
+		pager := s.dirClient(joinPath(src, rel)).NewListFilesAndDirectoriesPager(&directory.ListFilesAndDirectoriesOptions{
+			Include: directory.ListFilesInclude{
+				ETag: true,
+			},
+			MaxResults: to.Ptr(int32(maxListPageSize)),
+		})
+		for pager.More() {
+			output, err := pager.NextPage(ctx)
+			if err != nil {
+				pool.setError(err)
+				return pool.Wait()
+			}
+
+			for _, v := range output.Segment.Directories {
+				dirs = append(dirs, joinPath(rel, *v.Name))
+			}
+			for _, v := range output.Segment.Files {
+				e := downloadEntry{
+					name: joinPath(rel, *v.Name),
+				}
+				if v.Properties != nil {
+					e.size = deref(v.Properties.ContentLength)
+					if v.Properties.ETag != nil {
+						e.etag = string(*v.Properties.ETag)
+					}
+				}
+				if manifest.has(e) {
+					continue
+				}
+
+				ok := pool.Go(ctx, func() error {
+					err := s.downloadLocalFile(ctx, joinPath(src, e.name), filepath.Join(local, filepath.FromSlash(e.name)), e, opt)
+					if err != nil {
+						return err
+					}
+					return manifest.add(e)
+				})
+				if !ok {
+					return pool.Wait()
+				}
+			}
+		}
+	}
+
+	return pool.Wait()
+}
+
+// downloadLocalFile will download path into the local file via a part file, the part
+// file left by an interrupted download of the same ETag will be resumed.
+func (s *Storage) downloadLocalFile(ctx context.Context, path, name string, e downloadEntry, opt pairStorageRead) error {
+	part := name + "." + strings.Trim(e.etag, `"`) + downloadPartSuffix
+
+	// The parts of other ETags are outdated, they could not be resumed anymore.
+	stale, err := filepath.Glob(globEscape(name) + ".*" + downloadPartSuffix)
+	if err != nil {
+		return err
+	}
+	for _, v := range stale {
+		if v != part {
+			_ = os.Remove(v)
+		}
+	}
+
+	f, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Content is written in order by read, so the part file never has holes.
+	// The part file could be completed if the process is killed before renamed.
+	if fi.Size() != e.size {
+		if fi.Size() > 0 {
+			opt.HasOffset = true
+			opt.Offset = fi.Size()
+		}
+		// The part file must not be mixed with the content of another version.
+		if e.etag != "" {
+			opt.HasIfMatch = true
+			opt.IfMatch = e.etag
+		}
+
+		_, err = s.read(ctx, path, f, opt)
+		if err != nil {
+			return err
+		}
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(part, name)
+}
+
+// globEscape will escape the meta characters of filepath.Match in name.
+func globEscape(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// downloadEntry is a file downloaded by DownloadDir.
+type downloadEntry struct {
+	// name is the path relative to the downloaded directory.
+	name string
+	etag string
+	size int64
+}
+
+// downloadManifest records the downloaded files in lines of "etag\tsize\tname".
+//
+// Lines are only appended, the last line of a name wins.
+type downloadManifest struct {
+	mu      sync.Mutex
+	f       *os.File
+	entries map[string]downloadEntry
+}
+
+func openDownloadManifest(name string) (*downloadManifest, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &downloadManifest{
+		f:       f,
+		entries: make(map[string]downloadEntry),
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// A line could be broken if the process is killed while writing, it's ignored.
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		m.entries[fields[2]] = downloadEntry{name: fields[2], etag: fields[0], size: size}
+	}
+	if err = scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// has will check whether e has been downloaded and the local file still exists.
+func (m *downloadManifest) has(e downloadEntry) bool {
+	m.mu.Lock()
+	v, ok := m.entries[e.name]
+	m.mu.Unlock()
+	if !ok || e.etag == "" || v != e {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(m.f.Name()), filepath.FromSlash(e.name)))
+	return err == nil
+}
+
+func (m *downloadManifest) add(e downloadEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.f, "%s\t%d\t%s\n", e.etag, e.size, e.name)
+	if err != nil {
+		return err
+	}
+	m.entries[e.name] = e
+	return nil
+}
+
+func (m *downloadManifest) Close() error {
+	return m.f.Close()
+}
@@ -0,0 +1,33 @@
+package azfile
+
+// Operations recorded by dry run.
+const (
+	DryRunOpDelete = "delete"
+	DryRunOpMove   = "move"
+	DryRunOpUpload = "upload"
+	DryRunOpCopy   = "copy"
+)
+
+// DryRunOp is a destructive operation skipped by dry run.
+type DryRunOp struct {
+	// Op is one of the DryRunOp* constants.
+	Op string
+	// Path is the path to be deleted, moved or written.
+	Path string
+	// Src is the source of move, and the relative path in the sync source for upload and copy.
+	Src string
+}
+
+// DryRunFunc will be called with every operation skipped by dry run, it could be
+// called concurrently by operations like DeleteMulti.
+type DryRunFunc func(op DryRunOp)
+
+// dryRun will report op and return true if dry run is enabled, the caller should
+// skip the operation then.
+func (s *Storage) dryRun(op DryRunOp) bool {
+	if s.dryRunFunc == nil {
+		return false
+	}
+	s.dryRunFunc(op)
+	return true
+}
@@ -0,0 +1,273 @@
+package azfile
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// EncryptionKey is the key encryption key used in client-side encryption.
+//
+// Every file will be encrypted by a random content key with AES-256-GCM, and the
+// content key will be wrapped by the key encryption key and stored in file metadata.
+type EncryptionKey struct {
+	// ID will be stored along with the wrapped content key, so that the key
+	// could be found by EncryptionKeyResolver after rotated.
+	ID string
+	// Key must be 32 bytes for AES-256.
+	Key []byte
+}
+
+// EncryptionKeyResolver will return the key encryption key with the given id.
+type EncryptionKeyResolver func(id string) ([]byte, error)
+
+const (
+	// encryptionSegmentSize is the size of plaintext encrypted in one GCM segment,
+	// so that a range of the file could be decrypted without downloading the whole file.
+	encryptionSegmentSize = 64 * 1024
+	// encryptionOverhead is the size of the GCM tag appended to every segment.
+	encryptionOverhead = 16
+
+	metadataEncryptionKey   = "bm_encryption_key"
+	metadataEncryptionKeyID = "bm_encryption_key_id"
+	metadataEncryptionSize  = "bm_encryption_size"
+)
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// validateEncryptionKey will make sure the key could be used by AES-256-GCM.
+func validateEncryptionKey(key EncryptionKey) error {
+	if len(key.Key) != 32 {
+		return fmt.Errorf("encryption key must be 32 bytes, got %d", len(key.Key))
+	}
+	return nil
+}
+
+// encryptedSize will return the size of the file which carries size bytes plaintext.
+func encryptedSize(size int64) int64 {
+	segments := (size + encryptionSegmentSize - 1) / encryptionSegmentSize
+	return size + segments*encryptionOverhead
+}
+
+// segmentNonce will build the nonce of the segment.
+//
+// The content key is random for every file, so the nonce only needs to be unique in
+// one file. The last segment is marked to detect truncation.
+func segmentNonce(index int64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, uint64(index))
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// newEncryption will generate a content key, and return the metadata which
+// carries the wrapped content key.
+func (s *Storage) newEncryption(size int64) (aead cipher.AEAD, metadata map[string]*string, err error) {
+	cek := make([]byte, 32)
+	if _, err = rand.Read(cek); err != nil {
+		return nil, nil, err
+	}
+
+	kek, err := newGCM(s.encryptionKey.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, kek.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	wrapped := kek.Seal(nonce, nonce, cek, nil)
+
+	aead, err = newGCM(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey := base64.StdEncoding.EncodeToString(wrapped)
+	plainSize := strconv.FormatInt(size, 10)
+	return aead, map[string]*string{
+		metadataEncryptionKey:   &wrappedKey,
+		metadataEncryptionKeyID: &s.encryptionKey.ID,
+		metadataEncryptionSize:  &plainSize,
+	}, nil
+}
+
+// openEncryption will unwrap the content key from the metadata of the file.
+//
+// ok will be false if the file is not encrypted.
+func (s *Storage) openEncryption(metadata map[string]string) (aead cipher.AEAD, size int64, ok bool, err error) {
+	wrappedKey, ok := metadata[metadataEncryptionKey]
+	if !ok {
+		return nil, 0, false, nil
+	}
+
+	size, err = strconv.ParseInt(metadata[metadataEncryptionSize], 10, 64)
+	if err != nil {
+		return nil, 0, true, fmt.Errorf("encrypted size is invalid: %w", err)
+	}
+
+	key := s.encryptionKey.Key
+	if id := metadata[metadataEncryptionKeyID]; id != s.encryptionKey.ID {
+		if s.encryptionKeyResolver == nil {
+			return nil, 0, true, fmt.Errorf("%w: %s", ErrEncryptionKeyNotFound, id)
+		}
+		key, err = s.encryptionKeyResolver(id)
+		if err != nil {
+			return nil, 0, true, fmt.Errorf("%w: %s: %v", ErrEncryptionKeyNotFound, id, err)
+		}
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	kek, err := newGCM(key)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	if len(wrapped) < kek.NonceSize() {
+		return nil, 0, true, fmt.Errorf("wrapped key is too short")
+	}
+	cek, err := kek.Open(nil, wrapped[:kek.NonceSize()], wrapped[kek.NonceSize():], nil)
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	aead, err = newGCM(cek)
+	return aead, size, true, err
+}
+
+// encryptReader will encrypt the plaintext read from r segment by segment.
+type encryptReader struct {
+	r         io.Reader
+	aead      cipher.AEAD
+	remaining int64
+	index     int64
+
+	plain []byte
+	out   []byte
+	// buf is the ciphertext which has not been read.
+	buf []byte
+}
+
+func newEncryptReader(r io.Reader, aead cipher.AEAD, size int64) *encryptReader {
+	return &encryptReader{
+		r:         r,
+		aead:      aead,
+		remaining: size,
+		plain:     make([]byte, encryptionSegmentSize),
+		out:       make([]byte, 0, encryptionSegmentSize+encryptionOverhead),
+	}
+}
+
+// Read implements io.Reader
+func (e *encryptReader) Read(p []byte) (int, error) {
+	if len(e.buf) == 0 {
+		if e.remaining <= 0 {
+			return 0, io.EOF
+		}
+
+		n := int64(encryptionSegmentSize)
+		if e.remaining < n {
+			n = e.remaining
+		}
+		if _, err := io.ReadFull(e.r, e.plain[:n]); err != nil {
+			return 0, err
+		}
+		e.remaining -= n
+
+		e.buf = e.aead.Seal(e.out[:0], segmentNonce(e.index, e.remaining == 0), e.plain[:n], nil)
+		e.index++
+	}
+
+	n := copy(p, e.buf)
+	e.buf = e.buf[n:]
+	return n, nil
+}
+
+// readEncrypted will decrypt count bytes start from offset of the plaintext into w.
+//
+// Only the segments which cover the range will be downloaded.
+func readEncrypted(ctx context.Context, client *file.Client, w io.Writer, aead cipher.AEAD, size, offset, count int64) (n int64, err error) {
+	if count <= 0 || offset+count > size {
+		count = size - offset
+	}
+	if count <= 0 {
+		return 0, nil
+	}
+
+	const encSegmentSize = encryptionSegmentSize + encryptionOverhead
+	first := offset / encryptionSegmentSize
+	last := (offset + count - 1) / encryptionSegmentSize
+	lastOfFile := (size - 1) / encryptionSegmentSize
+
+	encOffset := first * encSegmentSize
+	encEnd := (last + 1) * encSegmentSize
+	if total := encryptedSize(size); encEnd > total {
+		encEnd = total
+	}
+
+	output, err := client.DownloadStream(ctx, &file.DownloadStreamOptions{
+		Range: file.HTTPRange{Offset: encOffset, Count: encEnd - encOffset},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		cErr := output.Body.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+
+	buf := make([]byte, encSegmentSize)
+	plain := make([]byte, 0, encryptionSegmentSize)
+	for i := first; i <= last; i++ {
+		segSize := int64(encryptionSegmentSize)
+		if rest := size - i*encryptionSegmentSize; rest < segSize {
+			segSize = rest
+		}
+
+		_, err = io.ReadFull(output.Body, buf[:segSize+encryptionOverhead])
+		if err != nil {
+			return n, err
+		}
+		plain, err = aead.Open(plain[:0], segmentNonce(i, i == lastOfFile), buf[:segSize+encryptionOverhead], nil)
+		if err != nil {
+			return n, fmt.Errorf("decrypt segment %d: %w", i, err)
+		}
+
+		// Only the first and the last segments could be partially read.
+		start := int64(0)
+		if i == first {
+			start = offset - i*encryptionSegmentSize
+		}
+		end := segSize
+		if i == last {
+			end = offset + count - i*encryptionSegmentSize
+		}
+
+		written, err := w.Write(plain[start:end])
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
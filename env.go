@@ -0,0 +1,71 @@
+package azfile
+
+import (
+	"fmt"
+	"os"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/pkg/credential"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Environment variables read by NewServicerFromEnv and NewStoragerFromEnv.
+//
+// The credential variables are the same as azcopy and Azure CLI.
+//
+// ref: https://docs.microsoft.com/en-us/cli/azure/storage#environment-variables
+const (
+	EnvConnectionString = "AZURE_STORAGE_CONNECTION_STRING"
+	EnvAccount          = "AZURE_STORAGE_ACCOUNT"
+	EnvKey              = "AZURE_STORAGE_KEY"
+	EnvSASToken         = "AZURE_STORAGE_SAS_TOKEN"
+	// EnvShareName and EnvWorkDir are only read by NewStoragerFromEnv.
+	EnvShareName = "AZURE_STORAGE_SHARE_NAME"
+	EnvWorkDir   = "AZURE_STORAGE_WORK_DIR"
+)
+
+// NewServicerFromEnv will create Servicer with the endpoint and credential read
+// from environment variables.
+//
+// The pairs passed in take precedence over the environment variables.
+func NewServicerFromEnv(pairs ...types.Pair) (types.Servicer, error) {
+	return newServicer(append(pairs, pairsFromEnv(false)...)...)
+}
+
+// NewStoragerFromEnv will create Storager with the endpoint, credential, share
+// name and work dir read from environment variables.
+//
+// The pairs passed in take precedence over the environment variables.
+func NewStoragerFromEnv(pairs ...types.Pair) (types.Storager, error) {
+	_, store, err := newServicerAndStorager(append(pairs, pairsFromEnv(true)...)...)
+	return store, err
+}
+
+// pairsFromEnv will build pairs from the environment variables which are set.
+func pairsFromEnv(storage bool) (pairs []types.Pair) {
+	if v := os.Getenv(EnvConnectionString); v != "" {
+		pairs = append(pairs, WithConnectionString(v))
+	}
+
+	account := os.Getenv(EnvAccount)
+	if account != "" {
+		pairs = append(pairs, WithAccountName(account))
+	}
+	// Account key takes precedence over SAS token, the same as Azure CLI.
+	if v := os.Getenv(EnvKey); v != "" && account != "" {
+		pairs = append(pairs, ps.WithCredential(fmt.Sprintf("%s:%s:%s", credential.ProtocolHmac, account, v)))
+	} else if v := os.Getenv(EnvSASToken); v != "" {
+		pairs = append(pairs, ps.WithCredential(fmt.Sprintf("%s:%s", credential.ProtocolAPIKey, v)))
+	}
+
+	if !storage {
+		return pairs
+	}
+	if v := os.Getenv(EnvShareName); v != "" {
+		pairs = append(pairs, ps.WithName(v))
+	}
+	if v := os.Getenv(EnvWorkDir); v != "" {
+		pairs = append(pairs, ps.WithWorkDir(v))
+	}
+	return pairs
+}
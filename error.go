@@ -0,0 +1,60 @@
+package azfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// StorageError is the error returned by Azure File service.
+//
+// All errors returned by operations will wrap StorageError if the request has
+// been responded, use errors.As to get it:
+//
+//	var se *StorageError
+//	if errors.As(err, &se) {
+//		log.Println(se.RequestID)
+//	}
+type StorageError struct {
+	// Code is the error code returned by service, like "ShareNotFound".
+	//
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/file-service-error-codes
+	Code       string
+	StatusCode int
+	// RequestID is the x-ms-request-id returned by service, which is required by Azure support.
+	RequestID string
+	// ClientRequestID is the x-ms-client-request-id echoed by service, which is set by
+	// the client_request_id pair.
+	ClientRequestID string
+
+	// message is the error message of SDK, the SDK error is not wrapped per GSP-47.
+	message string
+}
+
+func newStorageError(e *azcore.ResponseError) *StorageError {
+	se := &StorageError{
+		Code:       e.ErrorCode,
+		StatusCode: e.StatusCode,
+		message:    e.Error(),
+	}
+	if e.RawResponse != nil {
+		se.RequestID = e.RawResponse.Header.Get("x-ms-request-id")
+		se.ClientRequestID = e.RawResponse.Header.Get(headerClientRequestID)
+	}
+	return se
+}
+
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("status %d, code %s, request id %s: %s", e.StatusCode, e.Code, e.RequestID, e.message)
+}
+
+// GetRequestID will return the x-ms-request-id carried by err, or an empty string
+// if err is not returned by service.
+func GetRequestID(err error) string {
+	var se *StorageError
+	if errors.As(err, &se) {
+		return se.RequestID
+	}
+	return ""
+}
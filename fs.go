@@ -0,0 +1,298 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	pathpkg "path"
+	"sort"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// FS will return a read-only fs.FS of the work dir.
+//
+// This function will create a context by default.
+func (s *Storage) FS() fs.FS {
+	return s.FSWithContext(context.Background())
+}
+
+// FSWithContext will return a read-only fs.FS of the work dir, which also implements
+// fs.StatFS and fs.ReadDirFS. All requests are sent with ctx.
+//
+// The files opened implement io.Seeker, so that they could be served by http.FileServer
+// with http.FS. Content is streamed like ReadStream, so files could not be opened while
+// client-side encryption or compression enabled. Names are slash-separated paths relative to the work
+// dir as required by fs.ValidPath, and "." is the work dir itself.
+func (s *Storage) FSWithContext(ctx context.Context) fs.FS {
+	return &storageFS{s: s, ctx: ctx}
+}
+
+type storageFS struct {
+	s   *Storage
+	ctx context.Context
+}
+
+// Open implements fs.FS
+func (f *storageFS) Open(name string) (fs.File, error) {
+	o, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if o.Mode.IsDir() {
+		return &fsDir{fs: f, name: name, o: o}, nil
+	}
+
+	if f.s.encryptionKey != nil {
+		err = fmt.Errorf("%w: open while client-side encryption enabled", services.ErrCapabilityInsufficient)
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	// The size listed is the size of compressed content, and the content could not be
+	// read from the middle.
+	if f.s.compression != "" {
+		err = fmt.Errorf("%w: open while compression enabled", services.ErrCapabilityInsufficient)
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	size, _ := o.GetContentLength()
+	return &fsFile{fs: f, name: name, o: o, size: size}, nil
+}
+
+// Stat implements fs.StatFS
+func (f *storageFS) Stat(name string) (fs.FileInfo, error) {
+	o, err := f.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fsFileInfo{o: o}, nil
+}
+
+// ReadDir implements fs.ReadDirFS
+func (f *storageFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return f.readDir(name)
+}
+
+// stat will stat name as a file first, then as a directory if the file doesn't exist.
+func (f *storageFS) stat(op, name string) (*types.Object, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		o := f.s.newObject(true)
+		o.ID = f.s.workDir
+		o.Mode = types.ModeDir
+		return o, nil
+	}
+
+	o, err := f.s.stat(f.ctx, name, pairStorageStat{})
+	if err != nil && checkError(err, fileNotFound) {
+		o, err = f.s.stat(f.ctx, name, pairStorageStat{HasObjectMode: true, ObjectMode: types.ModeDir})
+	}
+	if err != nil {
+		return nil, f.pathError(op, name, err)
+	}
+	return o, nil
+}
+
+// readDir will list all entries of the directory sorted by name, like os.ReadDir.
+func (f *storageFS) readDir(name string) ([]fs.DirEntry, error) {
+	dir := name
+	if dir == "." {
+		dir = ""
+	}
+	// The last modified time is only returned with extended info.
+	it, err := f.s.list(f.ctx, formatDirPath(dir), pairStorageList{HasListExtendedInfo: true, ListExtendedInfo: true})
+	if err != nil {
+		return nil, f.pathError("readdir", name, err)
+	}
+
+	var entries []fs.DirEntry
+	for {
+		o, err := it.Next()
+		if errors.Is(err, types.IterateDone) {
+			break
+		}
+		if err != nil {
+			return nil, f.pathError("readdir", name, err)
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(fsFileInfo{o: o}))
+	}
+	// Files and directories are listed in the lexicographic order separately.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// pathError will convert the not found error into fs.ErrNotExist.
+func (f *storageFS) pathError(op, name string, err error) error {
+	err = f.s.formatError(op, err, name)
+	if errors.Is(err, services.ErrObjectNotExist) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// fsFile streams the content from offset, the stream is opened again after seeked.
+type fsFile struct {
+	fs   *storageFS
+	name string
+	o    *types.Object
+	size int64
+
+	offset int64
+	r      *readStream
+}
+
+// Stat implements fs.File
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return fsFileInfo{o: f.o}, nil
+}
+
+// Read implements fs.File
+func (f *fsFile) Read(p []byte) (n int, err error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if f.r == nil {
+		f.r, err = f.fs.s.newReadStream(f.fs.ctx, f.name, pairStorageRead{HasOffset: true, Offset: f.offset})
+		if err != nil {
+			return 0, f.fs.pathError("read", f.name, err)
+		}
+	}
+
+	n, err = f.r.Read(p)
+	f.offset += int64(n)
+	if err != nil && err != io.EOF {
+		err = f.fs.pathError("read", f.name, err)
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (f *fsFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	if offset != f.offset && f.r != nil {
+		_ = f.r.Close()
+		f.r = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close implements fs.File
+func (f *fsFile) Close() error {
+	if f.r == nil {
+		return nil
+	}
+	err := f.r.Close()
+	f.r = nil
+	return err
+}
+
+// fsDir lists all entries while first read.
+type fsDir struct {
+	fs   *storageFS
+	name string
+	o    *types.Object
+
+	entries []fs.DirEntry
+	listed  bool
+}
+
+// Stat implements fs.File
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return fsFileInfo{o: d.o}, nil
+}
+
+// Read implements fs.File
+func (d *fsDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.fs.readDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.listed = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// Close implements fs.File
+func (d *fsDir) Close() error {
+	return nil
+}
+
+// fsFileInfo implements fs.FileInfo with the object, which is returned by Sys.
+type fsFileInfo struct {
+	o *types.Object
+}
+
+func (i fsFileInfo) Name() string {
+	if i.o.Path == "" {
+		return "."
+	}
+	return pathpkg.Base(i.o.Path)
+}
+
+func (i fsFileInfo) Size() int64 {
+	size, _ := i.o.GetContentLength()
+	return size
+}
+
+// Mode will return read-only permissions, the ACLs of Azure Files are not mapped.
+func (i fsFileInfo) Mode() fs.FileMode {
+	if i.o.Mode.IsDir() {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (i fsFileInfo) ModTime() time.Time {
+	t, _ := i.o.GetLastModified()
+	return t
+}
+
+func (i fsFileInfo) IsDir() bool {
+	return i.o.Mode.IsDir()
+}
+
+func (i fsFileInfo) Sys() interface{} {
+	return i.o
+}
@@ -25,6 +25,30 @@ const Type = "azfile"
 
 // ObjectSystemMetadata stores system metadata for object.
 type ObjectSystemMetadata struct {
+	// CacheControl is the Cache-Control header of the file
+	CacheControl string
+	// ContentDisposition is the Content-Disposition header of the file
+	ContentDisposition string
+	// ContentEncoding is the Content-Encoding header of the file
+	ContentEncoding string
+	// ContentLanguage is the Content-Language header of the file
+	ContentLanguage string
+	// FileAttributes is the SMB attributes of the file or directory, like `ReadOnly | Archive`
+	FileAttributes string
+	// FileChangeTime is the change time of the file or directory
+	FileChangeTime time.Time
+	// FileCreationTime is the creation time of the file or directory
+	FileCreationTime time.Time
+	// FileID is the file id of the file or directory
+	FileID string
+	// FileLastWriteTime is the last write time of the file or directory
+	FileLastWriteTime time.Time
+	// FilePermission is the permission of the file or directory in SDDL, only available while resolve_file_permission is set
+	FilePermission string
+	// FilePermissionKey is the key of the permission of the file or directory
+	FilePermissionKey string
+	// ParentID is the file id of the parent directory
+	ParentID string
 	// ServerEncrypted
 	ServerEncrypted bool
 }
@@ -50,6 +74,22 @@ func setObjectSystemMetadata(o *Object, sm ObjectSystemMetadata) {
 
 // StorageSystemMetadata stores system metadata for storage meta.
 type StorageSystemMetadata struct {
+	// ShareIncludedBurstIOPS is the burst IOPS of the premium share
+	ShareIncludedBurstIOPS int64
+	// ShareMaxBurstCreditsForIOPS is the maximum burst credits of IOPS of the premium share
+	ShareMaxBurstCreditsForIOPS int64
+	// ShareProvisionedBandwidthMibps is the provisioned bandwidth of the premium share in MiB/s
+	ShareProvisionedBandwidthMibps int32
+	// ShareProvisionedEgressMbps is the provisioned egress of the premium share in MB/s
+	ShareProvisionedEgressMbps int32
+	// ShareProvisionedIngressMbps is the provisioned ingress of the premium share in MB/s
+	ShareProvisionedIngressMbps int32
+	// ShareProvisionedIOPS is the provisioned IOPS of the premium share
+	ShareProvisionedIOPS int32
+	// ShareQuota is the quota of the share in GiB
+	ShareQuota int32
+	// ShareUsageBytes is the approximate size of the data stored in the share in bytes
+	ShareUsageBytes int64
 }
 
 // GetStorageSystemMetadata will get SystemMetadata from StorageMeta.
@@ -70,182 +110,3387 @@ func setStorageSystemMetadata(s *StorageMeta, sm StorageSystemMetadata) {
 	s.SetSystemMetadata(sm)
 }
 
-// WithDefaultStoragePairs will apply default_storage_pairs value to Options.
+// WithAccountName will apply account_name value to Options.
 //
-// DefaultStoragePairs set default pairs for storager actions
-func WithDefaultStoragePairs(v DefaultStoragePairs) Pair {
+// AccountName set the storage account name, the endpoint will be built from it if endpoint is not set, otherwise it will be appended into the path of endpoint like Azurite
+func WithAccountName(v string) Pair {
 	return Pair{
-		Key:   "default_storage_pairs",
+		Key:   "account_name",
+		Value: v,
+	}
+}
+
+// WithAllowTrailingDot will apply allow_trailing_dot value to Options.
+//
+// AllowTrailingDot keep the trailing dot of file and directory names instead of trimming it
+func WithAllowTrailingDot(v bool) Pair {
+	return Pair{
+		Key:   "allow_trailing_dot",
+		Value: v,
+	}
+}
+
+// WithAPIVersion will apply api_version value to Options.
+//
+// APIVersion pin the service version sent in `x-ms-version` header, like `2019-02-02` for Azure Stack Hub, the version should support all features used
+func WithAPIVersion(v string) Pair {
+	return Pair{
+		Key:   "api_version",
+		Value: v,
+	}
+}
+
+// WithAtomicWrite will apply atomic_write value to Options.
+//
+// AtomicWrite upload the content to a temporary file and rename it over the path on success, so that readers never observe a half-written file
+func WithAtomicWrite(v bool) Pair {
+	return Pair{
+		Key:   "atomic_write",
+		Value: v,
+	}
+}
+
+// WithBandwidthLimit will apply bandwidth_limit value to Options.
+//
+// BandwidthLimit limit the bytes read and written per second, shared by all operations of the storage
+func WithBandwidthLimit(v int64) Pair {
+	return Pair{
+		Key:   "bandwidth_limit",
+		Value: v,
+	}
+}
+
+// WithBufferPoolLimit will apply buffer_pool_limit value to Options.
+//
+// BufferPoolLimit limit the number of range buffers used by uploads at the same time, shared by all operations of the storage
+func WithBufferPoolLimit(v int) Pair {
+	return Pair{
+		Key:   "buffer_pool_limit",
+		Value: v,
+	}
+}
+
+// WithCacheControl will apply cache_control value to Options.
+//
+// CacheControl set the Cache-Control header of the file
+func WithCacheControl(v string) Pair {
+	return Pair{
+		Key:   "cache_control",
+		Value: v,
+	}
+}
+
+// WithCallOptions will apply call_options value to Options.
+//
+// CallOptions override the retry, telemetry and headers of requests sent by this operation
+func WithCallOptions(v CallOptions) Pair {
+	return Pair{
+		Key:   "call_options",
+		Value: v,
+	}
+}
+
+// WithCheckQuota will apply check_quota value to Options.
+//
+// CheckQuota check the usage of share against its quota before writing, so that the write fails fast if the share is full
+func WithCheckQuota(v bool) Pair {
+	return Pair{
+		Key:   "check_quota",
+		Value: v,
+	}
+}
+
+// WithCheckpointStore will apply checkpoint_store value to Options.
+//
+// CheckpointStore persist the progress of upload into the store, so that the interrupted upload could be resumed by writing the same path and size again
+func WithCheckpointStore(v CheckpointStore) Pair {
+	return Pair{
+		Key:   "checkpoint_store",
+		Value: v,
+	}
+}
+
+// WithChunkSize will apply chunk_size value to Options.
+//
+// ChunkSize set the size of every range uploaded to or downloaded from service, should not be larger than 4 MiB
+func WithChunkSize(v int64) Pair {
+	return Pair{
+		Key:   "chunk_size",
+		Value: v,
+	}
+}
+
+// WithClientRequestID will apply client_request_id value to Options.
+//
+// ClientRequestID set the x-ms-client-request-id of all requests sent by the operation, which is recorded in storage analytics logs
+func WithClientRequestID(v string) Pair {
+	return Pair{
+		Key:   "client_request_id",
+		Value: v,
+	}
+}
+
+// WithCompression will apply compression value to Options.
+//
+// Compression compress the content on write with the content encoding, only gzip is supported, and decompress the files with it on Read, while ReadStream and ReadIntoWriterAt return the content as stored
+func WithCompression(v string) Pair {
+	return Pair{
+		Key:   "compression",
+		Value: v,
+	}
+}
+
+// WithCompressionThreshold will apply compression_threshold value to Options.
+//
+// CompressionThreshold files smaller than the threshold are written without compression, files with unknown size are always compressed
+func WithCompressionThreshold(v int64) Pair {
+	return Pair{
+		Key:   "compression_threshold",
+		Value: v,
+	}
+}
+
+// WithComputeContentMd5 will apply compute_content_md5 value to Options.
+//
+// ComputeContentMd5 compute the MD5 of the whole content while uploading and set it as the Content-MD5 of the file, ignored if content_md5 is given
+func WithComputeContentMd5(v bool) Pair {
+	return Pair{
+		Key:   "compute_content_md5",
+		Value: v,
+	}
+}
+
+// WithConcurrency will apply concurrency value to Options.
+//
+// Concurrency set the max number of concurrent requests issued in one operation
+func WithConcurrency(v int) Pair {
+	return Pair{
+		Key:   "concurrency",
+		Value: v,
+	}
+}
+
+// WithConnectionString will apply connection_string value to Options.
+//
+// ConnectionString set the Azure storage connection string, which contains both endpoint and credential
+func WithConnectionString(v string) Pair {
+	return Pair{
+		Key:   "connection_string",
+		Value: v,
+	}
+}
+
+// WithContentDisposition will apply content_disposition value to Options.
+//
+// ContentDisposition set the Content-Disposition header of the file
+func WithContentDisposition(v string) Pair {
+	return Pair{
+		Key:   "content_disposition",
+		Value: v,
+	}
+}
+
+// WithContentEncoding will apply content_encoding value to Options.
+//
+// ContentEncoding set the Content-Encoding header of the file
+func WithContentEncoding(v string) Pair {
+	return Pair{
+		Key:   "content_encoding",
+		Value: v,
+	}
+}
+
+// WithContentLanguage will apply content_language value to Options.
+//
+// ContentLanguage set the Content-Language header of the file
+func WithContentLanguage(v string) Pair {
+	return Pair{
+		Key:   "content_language",
+		Value: v,
+	}
+}
+
+// WithCopyProgress will apply copy_progress value to Options.
+//
+// CopyProgress specify the func which will be called with the status of the pending server-side copy
+func WithCopyProgress(v CopyProgressFunc) Pair {
+	return Pair{
+		Key:   "copy_progress",
+		Value: v,
+	}
+}
+
+// WithCopySMBInfo will apply copy_smb_info value to Options.
+//
+// CopySMBInfo keep the SMB attributes, timestamps and permission of the source file while copying
+func WithCopySMBInfo(v bool) Pair {
+	return Pair{
+		Key:   "copy_smb_info",
+		Value: v,
+	}
+}
+
+// WithCreateOnly will apply create_only value to Options.
+//
+// CreateOnly fail with ErrConditionNotMet if the file exists, the content is uploaded to a temporary file and renamed without replacing, so that concurrent writers could not be clobbered
+func WithCreateOnly(v bool) Pair {
+	return Pair{
+		Key:   "create_only",
+		Value: v,
+	}
+}
+
+// WithCreateParents will apply create_parents value to Options.
+//
+// CreateParents create the missing parent directories like `mkdir -p`, it's always enabled with virtual_dir feature
+func WithCreateParents(v bool) Pair {
+	return Pair{
+		Key:   "create_parents",
+		Value: v,
+	}
+}
+
+// WithDefaultAtomicWrite will apply default_atomic_write value to Options.
+//
+// DefaultAtomicWrite upload the content to a temporary file and rename it over the path on success, so that readers never observe a half-written file
+func WithDefaultAtomicWrite(v bool) Pair {
+	return Pair{
+		Key:   "default_atomic_write",
+		Value: v,
+	}
+}
+
+// WithDefaultCacheControl will apply default_cache_control value to Options.
+//
+// DefaultCacheControl set the Cache-Control header of the file
+func WithDefaultCacheControl(v string) Pair {
+	return Pair{
+		Key:   "default_cache_control",
+		Value: v,
+	}
+}
+
+// WithDefaultCheckQuota will apply default_check_quota value to Options.
+//
+// DefaultCheckQuota check the usage of share against its quota before writing, so that the write fails fast if the share is full
+func WithDefaultCheckQuota(v bool) Pair {
+	return Pair{
+		Key:   "default_check_quota",
+		Value: v,
+	}
+}
+
+// WithDefaultChunkSize will apply default_chunk_size value to Options.
+//
+// DefaultChunkSize set the size of every range uploaded to or downloaded from service, should not be larger than 4 MiB
+func WithDefaultChunkSize(v int64) Pair {
+	return Pair{
+		Key:   "default_chunk_size",
+		Value: v,
+	}
+}
+
+// WithDefaultComputeContentMd5 will apply default_compute_content_md5 value to Options.
+//
+// DefaultComputeContentMd5 compute the MD5 of the whole content while uploading and set it as the Content-MD5 of the file, ignored if content_md5 is given
+func WithDefaultComputeContentMd5(v bool) Pair {
+	return Pair{
+		Key:   "default_compute_content_md5",
+		Value: v,
+	}
+}
+
+// WithDefaultConcurrency will apply default_concurrency value to Options.
+//
+// DefaultConcurrency set the max number of concurrent requests issued in one operation
+func WithDefaultConcurrency(v int) Pair {
+	return Pair{
+		Key:   "default_concurrency",
+		Value: v,
+	}
+}
+
+// WithDefaultContentDisposition will apply default_content_disposition value to Options.
+//
+// DefaultContentDisposition set the Content-Disposition header of the file
+func WithDefaultContentDisposition(v string) Pair {
+	return Pair{
+		Key:   "default_content_disposition",
+		Value: v,
+	}
+}
+
+// WithDefaultContentEncoding will apply default_content_encoding value to Options.
+//
+// DefaultContentEncoding set the Content-Encoding header of the file
+func WithDefaultContentEncoding(v string) Pair {
+	return Pair{
+		Key:   "default_content_encoding",
+		Value: v,
+	}
+}
+
+// WithDefaultContentLanguage will apply default_content_language value to Options.
+//
+// DefaultContentLanguage set the Content-Language header of the file
+func WithDefaultContentLanguage(v string) Pair {
+	return Pair{
+		Key:   "default_content_language",
+		Value: v,
+	}
+}
+
+// WithDefaultCopySMBInfo will apply default_copy_smb_info value to Options.
+//
+// DefaultCopySMBInfo keep the SMB attributes, timestamps and permission of the source file while copying
+func WithDefaultCopySMBInfo(v bool) Pair {
+	return Pair{
+		Key:   "default_copy_smb_info",
+		Value: v,
+	}
+}
+
+// WithDefaultCreateOnly will apply default_create_only value to Options.
+//
+// DefaultCreateOnly fail with ErrConditionNotMet if the file exists, the content is uploaded to a temporary file and renamed without replacing, so that concurrent writers could not be clobbered
+func WithDefaultCreateOnly(v bool) Pair {
+	return Pair{
+		Key:   "default_create_only",
+		Value: v,
+	}
+}
+
+// WithDefaultCreateParents will apply default_create_parents value to Options.
+//
+// DefaultCreateParents create the missing parent directories like `mkdir -p`, it's always enabled with virtual_dir feature
+func WithDefaultCreateParents(v bool) Pair {
+	return Pair{
+		Key:   "default_create_parents",
+		Value: v,
+	}
+}
+
+// WithDefaultFileAttributes will apply default_file_attributes value to Options.
+//
+// DefaultFileAttributes set SMB attributes of files and directories, like `ReadOnly|Hidden`, available attributes are ReadOnly, Hidden, System, Archive, Temporary, Offline, NotContentIndexed and NoScrubData
+func WithDefaultFileAttributes(v string) Pair {
+	return Pair{
+		Key:   "default_file_attributes",
+		Value: v,
+	}
+}
+
+// WithDefaultFilePermission will apply default_file_permission value to Options.
+//
+// DefaultFilePermission set the permission of files and directories in SDDL
+func WithDefaultFilePermission(v string) Pair {
+	return Pair{
+		Key:   "default_file_permission",
+		Value: v,
+	}
+}
+
+// WithDefaultFilePermissionKey will apply default_file_permission_key value to Options.
+//
+// DefaultFilePermissionKey set the key of permission which has been created on the share
+func WithDefaultFilePermissionKey(v string) Pair {
+	return Pair{
+		Key:   "default_file_permission_key",
+		Value: v,
+	}
+}
+
+// WithDefaultLeaseFiles will apply default_lease_files value to Options.
+//
+// DefaultLeaseFiles acquire a lease on every file before it's deleted or copied from, so that it could not be modified by others during the operation, the lease will be released after the operation
+func WithDefaultLeaseFiles(v bool) Pair {
+	return Pair{
+		Key:   "default_lease_files",
+		Value: v,
+	}
+}
+
+// WithDefaultListExtendedInfo will apply default_list_extended_info value to Options.
+//
+// DefaultListExtendedInfo list with the last modified time, etag, file id, SMB attributes and timestamps of objects, so that they don't need to be stated
+func WithDefaultListExtendedInfo(v bool) Pair {
+	return Pair{
+		Key:   "default_list_extended_info",
+		Value: v,
+	}
+}
+
+// WithDefaultListPageSize will apply default_list_page_size value to Options.
+//
+// DefaultListPageSize set the max number of entries returned in one list page, should not be larger than 5000
+func WithDefaultListPageSize(v int) Pair {
+	return Pair{
+		Key:   "default_list_page_size",
+		Value: v,
+	}
+}
+
+// WithDefaultPartSize will apply default_part_size value to Options.
+//
+// DefaultPartSize set the size of every part except the last one in multipart upload
+func WithDefaultPartSize(v int64) Pair {
+	return Pair{
+		Key:   "default_part_size",
 		Value: v,
 	}
-}
+}
+
+// WithDefaultPreserveFileInfo will apply default_preserve_file_info value to Options.
+//
+// DefaultPreserveFileInfo set the last write time and ReadOnly attribute of the file from the local file which the content is read from, like *os.File
+func WithDefaultPreserveFileInfo(v bool) Pair {
+	return Pair{
+		Key:   "default_preserve_file_info",
+		Value: v,
+	}
+}
+
+// WithDefaultReadRetries will apply default_read_retries value to Options.
+//
+// DefaultReadRetries set the number of times a download will be requested again from where its body failed to be read, default to 3
+func WithDefaultReadRetries(v int) Pair {
+	return Pair{
+		Key:   "default_read_retries",
+		Value: v,
+	}
+}
+
+// WithDefaultServicePairs will apply default_service_pairs value to Options.
+//
+// DefaultServicePairs set default pairs for service actions
+func WithDefaultServicePairs(v DefaultServicePairs) Pair {
+	return Pair{
+		Key:   "default_service_pairs",
+		Value: v,
+	}
+}
+
+// WithDefaultStoragePairs will apply default_storage_pairs value to Options.
+//
+// DefaultStoragePairs set default pairs for storager actions
+func WithDefaultStoragePairs(v DefaultStoragePairs) Pair {
+	return Pair{
+		Key:   "default_storage_pairs",
+		Value: v,
+	}
+}
+
+// WithDefaultTimeout will apply default_timeout value to Options.
+//
+// DefaultTimeout set the deadline of the operation, which will also be sent to service as the server side timeout
+func WithDefaultTimeout(v time.Duration) Pair {
+	return Pair{
+		Key:   "default_timeout",
+		Value: v,
+	}
+}
+
+// WithDefaultTransactionalCRC64 will apply default_transactional_crc64 value to Options.
+//
+// DefaultTransactionalCRC64 use CRC64 instead of MD5 to validate every uploaded range, which is faster to compute
+func WithDefaultTransactionalCRC64(v bool) Pair {
+	return Pair{
+		Key:   "default_transactional_crc64",
+		Value: v,
+	}
+}
+
+// WithDefaultUserMetadata will apply default_user_metadata value to Options.
+//
+// DefaultUserMetadata set user defined metadata of files and directories
+func WithDefaultUserMetadata(v map[string]string) Pair {
+	return Pair{
+		Key:   "default_user_metadata",
+		Value: v,
+	}
+}
+
+// WithDefaultVerifyContentMd5 will apply default_verify_content_md5 value to Options.
+//
+// DefaultVerifyContentMd5 verify the downloaded content with the MD5 of every range returned by service
+func WithDefaultVerifyContentMd5(v bool) Pair {
+	return Pair{
+		Key:   "default_verify_content_md5",
+		Value: v,
+	}
+}
+
+// WithDefaultVerifyTransfer will apply default_verify_transfer value to Options.
+//
+// DefaultVerifyTransfer read the destination back after copy or write, and return TransferMismatchError if its size or MD5 doesn't match the source, ignored while writing with offset
+func WithDefaultVerifyTransfer(v bool) Pair {
+	return Pair{
+		Key:   "default_verify_transfer",
+		Value: v,
+	}
+}
+
+// WithDryRun will apply dry_run value to Options.
+//
+// DryRun skip the destructive operations like delete, move and applying sync plan, and report them to the func instead
+func WithDryRun(v DryRunFunc) Pair {
+	return Pair{
+		Key:   "dry_run",
+		Value: v,
+	}
+}
+
+// WithEnableLoosePair will apply enable_loose_pair value to Options.
+//
+// loose_pair feature is designed for users who don't want strict pair checks.
+//
+// If this feature is enabled, the service will not return an error for not support pairs.
+//
+// This feature was introduced in GSP-109.
+func WithEnableLoosePair() Pair {
+	return Pair{
+		Key:   "enable_loose_pair",
+		Value: true,
+	}
+}
+
+// WithEnableVirtualDir will apply enable_virtual_dir value to Options.
+//
+// virtual_dir feature is designed for a service that doesn't have native dir support but wants to provide simulated operations.
+//
+// - If this feature is disabled (the default behavior), the service will behave like it doesn't have any dir support.
+// - If this feature is enabled, the service will support simulated dir behavior in create_dir, create, list, delete, and so on.
+//
+// This feature was introduced in GSP-109.
+func WithEnableVirtualDir() Pair {
+	return Pair{
+		Key:   "enable_virtual_dir",
+		Value: true,
+	}
+}
+
+// WithEnableVirtualLink will apply enable_virtual_link value to Options.
+//
+// virtual_link feature is designed for a service that doesn't have native support for link.
+//
+// - If this feature is disabled (the default behavior), the service will only create native links.
+// - If this feature is enabled, the service will simulate links with objects which carry the link target in user metadata.
+//
+// This feature was introduced in GSP-86.
+func WithEnableVirtualLink() Pair {
+	return Pair{
+		Key:   "enable_virtual_link",
+		Value: true,
+	}
+}
+
+// WithEncryptionKey will apply encryption_key value to Options.
+//
+// EncryptionKey enable client-side encryption with the key, files will be encrypted on write and decrypted on read
+func WithEncryptionKey(v EncryptionKey) Pair {
+	return Pair{
+		Key:   "encryption_key",
+		Value: v,
+	}
+}
+
+// WithEncryptionKeyResolver will apply encryption_key_resolver value to Options.
+//
+// EncryptionKeyResolver specify the func to find the key which encrypted the file, used while the encryption key has been rotated
+func WithEncryptionKeyResolver(v EncryptionKeyResolver) Pair {
+	return Pair{
+		Key:   "encryption_key_resolver",
+		Value: v,
+	}
+}
+
+// WithEndpointSuffix will apply endpoint_suffix value to Options.
+//
+// EndpointSuffix set the endpoint suffix of the cloud while building endpoint from account name, like `core.chinacloudapi.cn`, default to `core.windows.net`
+func WithEndpointSuffix(v string) Pair {
+	return Pair{
+		Key:   "endpoint_suffix",
+		Value: v,
+	}
+}
+
+// WithFileAttributes will apply file_attributes value to Options.
+//
+// FileAttributes set SMB attributes of files and directories, like `ReadOnly|Hidden`, available attributes are ReadOnly, Hidden, System, Archive, Temporary, Offline, NotContentIndexed and NoScrubData
+func WithFileAttributes(v string) Pair {
+	return Pair{
+		Key:   "file_attributes",
+		Value: v,
+	}
+}
+
+// WithFileCreationTime will apply file_creation_time value to Options.
+//
+// FileCreationTime set the SMB creation time of files and directories instead of the current time
+func WithFileCreationTime(v time.Time) Pair {
+	return Pair{
+		Key:   "file_creation_time",
+		Value: v,
+	}
+}
+
+// WithFileLastWriteTime will apply file_last_write_time value to Options.
+//
+// FileLastWriteTime set the SMB last write time of files and directories instead of the current time
+func WithFileLastWriteTime(v time.Time) Pair {
+	return Pair{
+		Key:   "file_last_write_time",
+		Value: v,
+	}
+}
+
+// WithFilePermission will apply file_permission value to Options.
+//
+// FilePermission set the permission of files and directories in SDDL
+func WithFilePermission(v string) Pair {
+	return Pair{
+		Key:   "file_permission",
+		Value: v,
+	}
+}
+
+// WithFilePermissionKey will apply file_permission_key value to Options.
+//
+// FilePermissionKey set the key of permission which has been created on the share
+func WithFilePermissionKey(v string) Pair {
+	return Pair{
+		Key:   "file_permission_key",
+		Value: v,
+	}
+}
+
+// WithHardLink will apply hard_link value to Options.
+//
+// HardLink create a hard link instead of symbolic link, only supported in NFS shares
+func WithHardLink(v bool) Pair {
+	return Pair{
+		Key:   "hard_link",
+		Value: v,
+	}
+}
+
+// WithHTTPTransport will apply http_transport value to Options.
+//
+// HTTPTransport set the transport to send requests, like a transport with proxy or mTLS
+func WithHTTPTransport(v http.RoundTripper) Pair {
+	return Pair{
+		Key:   "http_transport",
+		Value: v,
+	}
+}
+
+// WithIfMatch will apply if_match value to Options.
+//
+// IfMatch only perform the operation if the etag of the file matches, `*` matches any existing file
+func WithIfMatch(v string) Pair {
+	return Pair{
+		Key:   "if_match",
+		Value: v,
+	}
+}
+
+// WithIfModifiedSince will apply if_modified_since value to Options.
+//
+// IfModifiedSince only perform the operation if the file has been modified since the time
+func WithIfModifiedSince(v time.Time) Pair {
+	return Pair{
+		Key:   "if_modified_since",
+		Value: v,
+	}
+}
+
+// WithIfNoneMatch will apply if_none_match value to Options.
+//
+// IfNoneMatch only perform the operation if the etag of the file doesn't match, `*` means the file must not exist
+func WithIfNoneMatch(v string) Pair {
+	return Pair{
+		Key:   "if_none_match",
+		Value: v,
+	}
+}
+
+// WithIfUnmodifiedSince will apply if_unmodified_since value to Options.
+//
+// IfUnmodifiedSince only perform the operation if the file has not been modified since the time
+func WithIfUnmodifiedSince(v time.Time) Pair {
+	return Pair{
+		Key:   "if_unmodified_since",
+		Value: v,
+	}
+}
+
+// WithLeaseFiles will apply lease_files value to Options.
+//
+// LeaseFiles acquire a lease on every file before it's deleted or copied from, so that it could not be modified by others during the operation, the lease will be released after the operation
+func WithLeaseFiles(v bool) Pair {
+	return Pair{
+		Key:   "lease_files",
+		Value: v,
+	}
+}
+
+// WithLeaseID will apply lease_id value to Options.
+//
+// LeaseID set the id of the active lease on the file
+func WithLeaseID(v string) Pair {
+	return Pair{
+		Key:   "lease_id",
+		Value: v,
+	}
+}
+
+// WithListExtendedInfo will apply list_extended_info value to Options.
+//
+// ListExtendedInfo list with the last modified time, etag, file id, SMB attributes and timestamps of objects, so that they don't need to be stated
+func WithListExtendedInfo(v bool) Pair {
+	return Pair{
+		Key:   "list_extended_info",
+		Value: v,
+	}
+}
+
+// WithListMaxSize will apply list_max_size value to Options.
+//
+// ListMaxSize only list files not larger than the size in bytes
+func WithListMaxSize(v int64) Pair {
+	return Pair{
+		Key:   "list_max_size",
+		Value: v,
+	}
+}
+
+// WithListMinSize will apply list_min_size value to Options.
+//
+// ListMinSize only list files not smaller than the size in bytes
+func WithListMinSize(v int64) Pair {
+	return Pair{
+		Key:   "list_min_size",
+		Value: v,
+	}
+}
+
+// WithListModifiedAfter will apply list_modified_after value to Options.
+//
+// ListModifiedAfter only list files last modified after the time
+func WithListModifiedAfter(v time.Time) Pair {
+	return Pair{
+		Key:   "list_modified_after",
+		Value: v,
+	}
+}
+
+// WithListModifiedBefore will apply list_modified_before value to Options.
+//
+// ListModifiedBefore only list files last modified before the time, like 30 days ago to find stale files
+func WithListModifiedBefore(v time.Time) Pair {
+	return Pair{
+		Key:   "list_modified_before",
+		Value: v,
+	}
+}
+
+// WithListNameRegexp will apply list_name_regexp value to Options.
+//
+// ListNameRegexp only list files whose name matches the regular expression, directories are not filtered
+func WithListNameRegexp(v string) Pair {
+	return Pair{
+		Key:   "list_name_regexp",
+		Value: v,
+	}
+}
+
+// WithListNameSuffix will apply list_name_suffix value to Options.
+//
+// ListNameSuffix only list files whose name ends with the suffix, directories are not filtered
+func WithListNameSuffix(v string) Pair {
+	return Pair{
+		Key:   "list_name_suffix",
+		Value: v,
+	}
+}
+
+// WithListPageSize will apply list_page_size value to Options.
+//
+// ListPageSize set the max number of entries returned in one list page, should not be larger than 5000
+func WithListPageSize(v int) Pair {
+	return Pair{
+		Key:   "list_page_size",
+		Value: v,
+	}
+}
+
+// WithMetricsCollector will apply metrics_collector value to Options.
+//
+// MetricsCollector set the collector which receives the metrics of requests
+func WithMetricsCollector(v MetricsCollector) Pair {
+	return Pair{
+		Key:   "metrics_collector",
+		Value: v,
+	}
+}
+
+// WithPartSize will apply part_size value to Options.
+//
+// PartSize set the size of every part except the last one in multipart upload
+func WithPartSize(v int64) Pair {
+	return Pair{
+		Key:   "part_size",
+		Value: v,
+	}
+}
+
+// WithPathMapper will apply path_mapper value to Options.
+//
+// PathMapper map the paths of files to the paths stored in the share, the listed files are mapped back
+func WithPathMapper(v PathMapper) Pair {
+	return Pair{
+		Key:   "path_mapper",
+		Value: v,
+	}
+}
+
+// WithPathValidation will apply path_validation value to Options.
+//
+// PathValidation check the paths to create against the naming rules of Azure Files before any request sent, reject or sanitize the invalid ones
+func WithPathValidation(v string) Pair {
+	return Pair{
+		Key:   "path_validation",
+		Value: v,
+	}
+}
+
+// WithPreserveFileInfo will apply preserve_file_info value to Options.
+//
+// PreserveFileInfo set the last write time and ReadOnly attribute of the file from the local file which the content is read from, like *os.File
+func WithPreserveFileInfo(v bool) Pair {
+	return Pair{
+		Key:   "preserve_file_info",
+		Value: v,
+	}
+}
+
+// WithProgress will apply progress value to Options.
+//
+// Progress specify the func which will be called with the number of objects processed and bytes transferred
+func WithProgress(v ProgressFunc) Pair {
+	return Pair{
+		Key:   "progress",
+		Value: v,
+	}
+}
+
+// WithReadOnly will apply read_only value to Options.
+//
+// ReadOnly reject all operations which modify the share with ErrReadOnly before any request sent, including leases, snapshots and signing for write
+func WithReadOnly(v bool) Pair {
+	return Pair{
+		Key:   "read_only",
+		Value: v,
+	}
+}
+
+// WithReadRetries will apply read_retries value to Options.
+//
+// ReadRetries set the number of times a download will be requested again from where its body failed to be read, default to 3
+func WithReadRetries(v int) Pair {
+	return Pair{
+		Key:   "read_retries",
+		Value: v,
+	}
+}
+
+// WithRequestLogger will apply request_logger value to Options.
+//
+// RequestLogger set the logger which will be called after every try of requests
+func WithRequestLogger(v RequestLogger) Pair {
+	return Pair{
+		Key:   "request_logger",
+		Value: v,
+	}
+}
+
+// WithResolveFilePermission will apply resolve_file_permission value to Options.
+//
+// ResolveFilePermission resolve the permission key into SDDL in stat, it will send an extra request
+func WithResolveFilePermission(v bool) Pair {
+	return Pair{
+		Key:   "resolve_file_permission",
+		Value: v,
+	}
+}
+
+// WithRetryOptions will apply retry_options value to Options.
+//
+// RetryOptions set the retry policy of requests, retry is disabled by default
+func WithRetryOptions(v RetryOptions) Pair {
+	return Pair{
+		Key:   "retry_options",
+		Value: v,
+	}
+}
+
+// WithSASProvider will apply sas_provider value to Options.
+//
+// SASProvider specify the func to get fresh SAS token, which will be called before the token expires
+func WithSASProvider(v SASProvider) Pair {
+	return Pair{
+		Key:   "sas_provider",
+		Value: v,
+	}
+}
+
+// WithServiceFeatures will apply service_features value to Options.
+//
+// ServiceFeatures set service features
+func WithServiceFeatures(v ServiceFeatures) Pair {
+	return Pair{
+		Key:   "service_features",
+		Value: v,
+	}
+}
+
+// WithShareAccessTier will apply share_access_tier value to Options.
+//
+// ShareAccessTier set the access tier of share while creating, like `Hot`, `Cool` or `TransactionOptimized`
+func WithShareAccessTier(v string) Pair {
+	return Pair{
+		Key:   "share_access_tier",
+		Value: v,
+	}
+}
+
+// WithSharePrefix will apply share_prefix value to Options.
+//
+// SharePrefix only list shares whose name begin with the specified prefix
+func WithSharePrefix(v string) Pair {
+	return Pair{
+		Key:   "share_prefix",
+		Value: v,
+	}
+}
+
+// WithShareProtocols will apply share_protocols value to Options.
+//
+// ShareProtocols set the enabled protocols of share while creating, `SMB` or `NFS`, default to `SMB`
+func WithShareProtocols(v string) Pair {
+	return Pair{
+		Key:   "share_protocols",
+		Value: v,
+	}
+}
+
+// WithShareQuota will apply share_quota value to Options.
+//
+// ShareQuota set the quota of share in GiB
+func WithShareQuota(v int32) Pair {
+	return Pair{
+		Key:   "share_quota",
+		Value: v,
+	}
+}
+
+// WithShareRootSquash will apply share_root_squash value to Options.
+//
+// ShareRootSquash set the root squash of NFS share while creating, `NoRootSquash`, `RootSquash` or `AllSquash`
+func WithShareRootSquash(v string) Pair {
+	return Pair{
+		Key:   "share_root_squash",
+		Value: v,
+	}
+}
+
+// WithShareSnapshot will apply share_snapshot value to Options.
+//
+// ShareSnapshot pin all operations of the storager to the specified share snapshot
+func WithShareSnapshot(v string) Pair {
+	return Pair{
+		Key:   "share_snapshot",
+		Value: v,
+	}
+}
+
+// WithSourceShare will apply source_share value to Options.
+//
+// SourceShare copy from the file in another share of the same account, the source path is resolved from the root of the share
+func WithSourceShare(v string) Pair {
+	return Pair{
+		Key:   "source_share",
+		Value: v,
+	}
+}
+
+// WithStatCacheTTL will apply stat_cache_ttl value to Options.
+//
+// StatCacheTTL cache the result of stat for the duration, the cache will be invalidated by the operations which change the path
+func WithStatCacheTTL(v time.Duration) Pair {
+	return Pair{
+		Key:   "stat_cache_ttl",
+		Value: v,
+	}
+}
+
+// WithStorageFeatures will apply storage_features value to Options.
+//
+// StorageFeatures set storage features
+func WithStorageFeatures(v StorageFeatures) Pair {
+	return Pair{
+		Key:   "storage_features",
+		Value: v,
+	}
+}
+
+// WithTimeout will apply timeout value to Options.
+//
+// Timeout set the deadline of the operation, which will also be sent to service as the server side timeout
+func WithTimeout(v time.Duration) Pair {
+	return Pair{
+		Key:   "timeout",
+		Value: v,
+	}
+}
+
+// WithTokenCredential will apply token_credential value to Options.
+//
+// TokenCredential set the token credential to authenticate with Azure AD, the file request intent will be set to backup
+func WithTokenCredential(v TokenCredential) Pair {
+	return Pair{
+		Key:   "token_credential",
+		Value: v,
+	}
+}
+
+// WithTokenProvider will apply token_provider value to Options.
+//
+// TokenProvider specify the func to get fresh OAuth token, which will be called before the token expires
+func WithTokenProvider(v TokenProvider) Pair {
+	return Pair{
+		Key:   "token_provider",
+		Value: v,
+	}
+}
+
+// WithTracerProvider will apply tracer_provider value to Options.
+//
+// TracerProvider set the OpenTelemetry tracer provider to trace storage operations, default to the global provider
+func WithTracerProvider(v TracerProvider) Pair {
+	return Pair{
+		Key:   "tracer_provider",
+		Value: v,
+	}
+}
+
+// WithTransactionalCRC64 will apply transactional_crc64 value to Options.
+//
+// TransactionalCRC64 use CRC64 instead of MD5 to validate every uploaded range, which is faster to compute
+func WithTransactionalCRC64(v bool) Pair {
+	return Pair{
+		Key:   "transactional_crc64",
+		Value: v,
+	}
+}
+
+// WithTransportOptions will apply transport_options value to Options.
+//
+// TransportOptions tune the connection pool, timeouts, TLS session reuse and HTTP/2 of the default transport, could not be used with http_transport
+func WithTransportOptions(v TransportOptions) Pair {
+	return Pair{
+		Key:   "transport_options",
+		Value: v,
+	}
+}
+
+// WithUserMetadata will apply user_metadata value to Options.
+//
+// UserMetadata set user defined metadata of files and directories
+func WithUserMetadata(v map[string]string) Pair {
+	return Pair{
+		Key:   "user_metadata",
+		Value: v,
+	}
+}
+
+// WithVerifyContentMd5 will apply verify_content_md5 value to Options.
+//
+// VerifyContentMd5 verify the downloaded content with the MD5 of every range returned by service
+func WithVerifyContentMd5(v bool) Pair {
+	return Pair{
+		Key:   "verify_content_md5",
+		Value: v,
+	}
+}
+
+// WithVerifyTransfer will apply verify_transfer value to Options.
+//
+// VerifyTransfer read the destination back after copy or write, and return TransferMismatchError if its size or MD5 doesn't match the source, ignored while writing with offset
+func WithVerifyTransfer(v bool) Pair {
+	return Pair{
+		Key:   "verify_transfer",
+		Value: v,
+	}
+}
+
+var pairMap = map[string]string{
+	"account_name":                "string",
+	"allow_trailing_dot":          "bool",
+	"api_version":                 "string",
+	"atomic_write":                "bool",
+	"bandwidth_limit":             "int64",
+	"buffer_pool_limit":           "int",
+	"cache_control":               "string",
+	"call_options":                "CallOptions",
+	"check_quota":                 "bool",
+	"checkpoint_store":            "CheckpointStore",
+	"chunk_size":                  "int64",
+	"client_request_id":           "string",
+	"compression":                 "string",
+	"compression_threshold":       "int64",
+	"compute_content_md5":         "bool",
+	"concurrency":                 "int",
+	"connection_string":           "string",
+	"content_disposition":         "string",
+	"content_encoding":            "string",
+	"content_language":            "string",
+	"content_md5":                 "string",
+	"content_type":                "string",
+	"context":                     "context.Context",
+	"continuation_token":          "string",
+	"copy_progress":               "CopyProgressFunc",
+	"copy_smb_info":               "bool",
+	"create_only":                 "bool",
+	"create_parents":              "bool",
+	"credential":                  "string",
+	"default_atomic_write":        "bool",
+	"default_cache_control":       "string",
+	"default_check_quota":         "bool",
+	"default_chunk_size":          "int64",
+	"default_compute_content_md5": "bool",
+	"default_concurrency":         "int",
+	"default_content_disposition": "string",
+	"default_content_encoding":    "string",
+	"default_content_language":    "string",
+	"default_copy_smb_info":       "bool",
+	"default_create_only":         "bool",
+	"default_create_parents":      "bool",
+	"default_file_attributes":     "string",
+	"default_file_permission":     "string",
+	"default_file_permission_key": "string",
+	"default_lease_files":         "bool",
+	"default_list_extended_info":  "bool",
+	"default_list_page_size":      "int",
+	"default_part_size":           "int64",
+	"default_preserve_file_info":  "bool",
+	"default_read_retries":        "int",
+	"default_service_pairs":       "DefaultServicePairs",
+	"default_storage_pairs":       "DefaultStoragePairs",
+	"default_timeout":             "time.Duration",
+	"default_transactional_crc64": "bool",
+	"default_user_metadata":       "map[string]string",
+	"default_verify_content_md5":  "bool",
+	"default_verify_transfer":     "bool",
+	"dry_run":                     "DryRunFunc",
+	"enable_loose_pair":           "bool",
+	"enable_virtual_dir":          "bool",
+	"enable_virtual_link":         "bool",
+	"encryption_key":              "EncryptionKey",
+	"encryption_key_resolver":     "EncryptionKeyResolver",
+	"endpoint":                    "string",
+	"endpoint_suffix":             "string",
+	"expire":                      "time.Duration",
+	"file_attributes":             "string",
+	"file_creation_time":          "time.Time",
+	"file_last_write_time":        "time.Time",
+	"file_permission":             "string",
+	"file_permission_key":         "string",
+	"hard_link":                   "bool",
+	"http_client_options":         "*httpclient.Options",
+	"http_transport":              "http.RoundTripper",
+	"if_match":                    "string",
+	"if_modified_since":           "time.Time",
+	"if_none_match":               "string",
+	"if_unmodified_since":         "time.Time",
+	"interceptor":                 "Interceptor",
+	"io_callback":                 "func([]byte)",
+	"lease_files":                 "bool",
+	"lease_id":                    "string",
+	"list_extended_info":          "bool",
+	"list_max_size":               "int64",
+	"list_min_size":               "int64",
+	"list_mode":                   "ListMode",
+	"list_modified_after":         "time.Time",
+	"list_modified_before":        "time.Time",
+	"list_name_regexp":            "string",
+	"list_name_suffix":            "string",
+	"list_page_size":              "int",
+	"location":                    "string",
+	"metrics_collector":           "MetricsCollector",
+	"multipart_id":                "string",
+	"name":                        "string",
+	"object_mode":                 "ObjectMode",
+	"offset":                      "int64",
+	"part_size":                   "int64",
+	"path_mapper":                 "PathMapper",
+	"path_validation":             "string",
+	"preserve_file_info":          "bool",
+	"progress":                    "ProgressFunc",
+	"read_only":                   "bool",
+	"read_retries":                "int",
+	"request_logger":              "RequestLogger",
+	"resolve_file_permission":     "bool",
+	"retry_options":               "RetryOptions",
+	"sas_provider":                "SASProvider",
+	"service_features":            "ServiceFeatures",
+	"share_access_tier":           "string",
+	"share_prefix":                "string",
+	"share_protocols":             "string",
+	"share_quota":                 "int32",
+	"share_root_squash":           "string",
+	"share_snapshot":              "string",
+	"size":                        "int64",
+	"source_share":                "string",
+	"stat_cache_ttl":              "time.Duration",
+	"storage_features":            "StorageFeatures",
+	"timeout":                     "time.Duration",
+	"token_credential":            "TokenCredential",
+	"token_provider":              "TokenProvider",
+	"tracer_provider":             "TracerProvider",
+	"transactional_crc64":         "bool",
+	"transport_options":           "TransportOptions",
+	"user_metadata":               "map[string]string",
+	"verify_content_md5":          "bool",
+	"verify_transfer":             "bool",
+	"work_dir":                    "string",
+}
+var (
+	_ Servicer = &Service{}
+)
+
+type ServiceFeatures struct {
+	// LoosePair loose_pair feature is designed for users who don't want strict pair checks.
+	//
+	// If this feature is enabled, the service will not return an error for not support pairs.
+	//
+	// This feature was introduced in GSP-109.
+	LoosePair bool
+}
+
+// Implements will return whether Service implements the interface with given name, like "Servicer".
+//
+// The Unimplemented structs are embedded into Service, so type assertion always succeeds.
+func (s *Service) Implements(name string) bool {
+	switch name {
+	case "Servicer":
+		return true
+	default:
+		return false
+	}
+}
+
+// pairServiceNew is the parsed struct
+type pairServiceNew struct {
+	pairs []Pair
+
+	// Required pairs
+	// Optional pairs
+	HasAccountName         bool
+	AccountName            string
+	HasAllowTrailingDot    bool
+	AllowTrailingDot       bool
+	HasAPIVersion          bool
+	APIVersion             string
+	HasConnectionString    bool
+	ConnectionString       string
+	HasCredential          bool
+	Credential             string
+	HasDefaultServicePairs bool
+	DefaultServicePairs    DefaultServicePairs
+	HasEndpoint            bool
+	Endpoint               string
+	HasEndpointSuffix      bool
+	EndpointSuffix         string
+	HasHTTPTransport       bool
+	HTTPTransport          http.RoundTripper
+	HasMetricsCollector    bool
+	MetricsCollector       MetricsCollector
+	HasRequestLogger       bool
+	RequestLogger          RequestLogger
+	HasRetryOptions        bool
+	RetryOptions           RetryOptions
+	HasSASProvider         bool
+	SASProvider            SASProvider
+	HasServiceFeatures     bool
+	ServiceFeatures        ServiceFeatures
+	HasTokenCredential     bool
+	TokenCredential        TokenCredential
+	HasTokenProvider       bool
+	TokenProvider          TokenProvider
+	HasTracerProvider      bool
+	TracerProvider         TracerProvider
+	HasTransportOptions    bool
+	TransportOptions       TransportOptions
+	// Enable features
+	hasEnableLoosePair bool
+	EnableLoosePair    bool
+	// Default pairs
+}
+
+// parsePairServiceNew will parse Pair slice into *pairServiceNew
+func parsePairServiceNew(opts []Pair) (pairServiceNew, error) {
+	result := pairServiceNew{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		// Required pairs
+		// Optional pairs
+		case "account_name":
+			if result.HasAccountName {
+				continue
+			}
+			result.HasAccountName = true
+			result.AccountName = v.Value.(string)
+		case "allow_trailing_dot":
+			if result.HasAllowTrailingDot {
+				continue
+			}
+			result.HasAllowTrailingDot = true
+			result.AllowTrailingDot = v.Value.(bool)
+		case "api_version":
+			if result.HasAPIVersion {
+				continue
+			}
+			result.HasAPIVersion = true
+			result.APIVersion = v.Value.(string)
+		case "connection_string":
+			if result.HasConnectionString {
+				continue
+			}
+			result.HasConnectionString = true
+			result.ConnectionString = v.Value.(string)
+		case "credential":
+			if result.HasCredential {
+				continue
+			}
+			result.HasCredential = true
+			result.Credential = v.Value.(string)
+		case "default_service_pairs":
+			if result.HasDefaultServicePairs {
+				continue
+			}
+			result.HasDefaultServicePairs = true
+			result.DefaultServicePairs = v.Value.(DefaultServicePairs)
+		case "endpoint":
+			if result.HasEndpoint {
+				continue
+			}
+			result.HasEndpoint = true
+			result.Endpoint = v.Value.(string)
+		case "endpoint_suffix":
+			if result.HasEndpointSuffix {
+				continue
+			}
+			result.HasEndpointSuffix = true
+			result.EndpointSuffix = v.Value.(string)
+		case "http_transport":
+			if result.HasHTTPTransport {
+				continue
+			}
+			result.HasHTTPTransport = true
+			result.HTTPTransport = v.Value.(http.RoundTripper)
+		case "metrics_collector":
+			if result.HasMetricsCollector {
+				continue
+			}
+			result.HasMetricsCollector = true
+			result.MetricsCollector = v.Value.(MetricsCollector)
+		case "request_logger":
+			if result.HasRequestLogger {
+				continue
+			}
+			result.HasRequestLogger = true
+			result.RequestLogger = v.Value.(RequestLogger)
+		case "retry_options":
+			if result.HasRetryOptions {
+				continue
+			}
+			result.HasRetryOptions = true
+			result.RetryOptions = v.Value.(RetryOptions)
+		case "sas_provider":
+			if result.HasSASProvider {
+				continue
+			}
+			result.HasSASProvider = true
+			result.SASProvider = v.Value.(SASProvider)
+		case "service_features":
+			if result.HasServiceFeatures {
+				continue
+			}
+			result.HasServiceFeatures = true
+			result.ServiceFeatures = v.Value.(ServiceFeatures)
+		case "token_credential":
+			if result.HasTokenCredential {
+				continue
+			}
+			result.HasTokenCredential = true
+			result.TokenCredential = v.Value.(TokenCredential)
+		case "token_provider":
+			if result.HasTokenProvider {
+				continue
+			}
+			result.HasTokenProvider = true
+			result.TokenProvider = v.Value.(TokenProvider)
+		case "tracer_provider":
+			if result.HasTracerProvider {
+				continue
+			}
+			result.HasTracerProvider = true
+			result.TracerProvider = v.Value.(TracerProvider)
+		case "transport_options":
+			if result.HasTransportOptions {
+				continue
+			}
+			result.HasTransportOptions = true
+			result.TransportOptions = v.Value.(TransportOptions)
+			// Enable features
+		case "enable_loose_pair":
+			if result.hasEnableLoosePair {
+				continue
+			}
+			result.hasEnableLoosePair = true
+			result.EnableLoosePair = true
+			// Default pairs
+		}
+	}
+
+	// Enable features
+	if result.hasEnableLoosePair {
+		result.HasServiceFeatures = true
+		result.ServiceFeatures.LoosePair = true
+	}
+
+	// Default pairs
+
+	return result, nil
+}
+
+// DefaultServicePairs is default pairs for specific action
+type DefaultServicePairs struct {
+	Create []Pair
+	Delete []Pair
+	Get    []Pair
+	List   []Pair
+}
+
+// pairServiceCreate is the parsed struct
+type pairServiceCreate struct {
+	pairs              []Pair
+	HasShareAccessTier bool
+	ShareAccessTier    string
+	HasShareProtocols  bool
+	ShareProtocols     string
+	HasShareQuota      bool
+	ShareQuota         int32
+	HasShareRootSquash bool
+	ShareRootSquash    string
+}
+
+// parsePairServiceCreate will parse Pair slice into *pairServiceCreate
+func (s *Service) parsePairServiceCreate(opts []Pair) (pairServiceCreate, error) {
+	result := pairServiceCreate{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "share_access_tier":
+			if result.HasShareAccessTier {
+				continue
+			}
+			result.HasShareAccessTier = true
+			result.ShareAccessTier = v.Value.(string)
+			continue
+		case "share_protocols":
+			if result.HasShareProtocols {
+				continue
+			}
+			result.HasShareProtocols = true
+			result.ShareProtocols = v.Value.(string)
+			continue
+		case "share_quota":
+			if result.HasShareQuota {
+				continue
+			}
+			result.HasShareQuota = true
+			result.ShareQuota = v.Value.(int32)
+			continue
+		case "share_root_squash":
+			if result.HasShareRootSquash {
+				continue
+			}
+			result.HasShareRootSquash = true
+			result.ShareRootSquash = v.Value.(string)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairServiceCreate{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairServiceDelete is the parsed struct
+type pairServiceDelete struct {
+	pairs []Pair
+}
+
+// parsePairServiceDelete will parse Pair slice into *pairServiceDelete
+func (s *Service) parsePairServiceDelete(opts []Pair) (pairServiceDelete, error) {
+	result := pairServiceDelete{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairServiceDelete{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairServiceGet is the parsed struct
+type pairServiceGet struct {
+	pairs []Pair
+}
+
+// parsePairServiceGet will parse Pair slice into *pairServiceGet
+func (s *Service) parsePairServiceGet(opts []Pair) (pairServiceGet, error) {
+	result := pairServiceGet{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairServiceGet{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairServiceList is the parsed struct
+type pairServiceList struct {
+	pairs          []Pair
+	HasSharePrefix bool
+	SharePrefix    string
+}
+
+// parsePairServiceList will parse Pair slice into *pairServiceList
+func (s *Service) parsePairServiceList(opts []Pair) (pairServiceList, error) {
+	result := pairServiceList{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "share_prefix":
+			if result.HasSharePrefix {
+				continue
+			}
+			result.HasSharePrefix = true
+			result.SharePrefix = v.Value.(string)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairServiceList{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// Create will create a new storager instance.
+//
+// This function will create a context by default.
+func (s *Service) Create(name string, pairs ...Pair) (store Storager, err error) {
+	ctx := context.Background()
+	return s.CreateWithContext(ctx, name, pairs...)
+}
+
+// CreateWithContext will create a new storager instance.
+func (s *Service) CreateWithContext(ctx context.Context, name string, pairs ...Pair) (store Storager, err error) {
+	defer func() {
+		err = s.formatError("create", err, name)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Create...)
+	var opt pairServiceCreate
+
+	opt, err = s.parsePairServiceCreate(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.create(ctx, name, opt)
+}
+
+// Delete will delete a storager instance.
+//
+// This function will create a context by default.
+func (s *Service) Delete(name string, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.DeleteWithContext(ctx, name, pairs...)
+}
+
+// DeleteWithContext will delete a storager instance.
+func (s *Service) DeleteWithContext(ctx context.Context, name string, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("delete", err, name)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Delete...)
+	var opt pairServiceDelete
+
+	opt, err = s.parsePairServiceDelete(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.delete(ctx, name, opt)
+}
+
+// Get will get a valid storager instance for service.
+//
+// This function will create a context by default.
+func (s *Service) Get(name string, pairs ...Pair) (store Storager, err error) {
+	ctx := context.Background()
+	return s.GetWithContext(ctx, name, pairs...)
+}
+
+// GetWithContext will get a valid storager instance for service.
+func (s *Service) GetWithContext(ctx context.Context, name string, pairs ...Pair) (store Storager, err error) {
+	defer func() {
+		err = s.formatError("get", err, name)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Get...)
+	var opt pairServiceGet
+
+	opt, err = s.parsePairServiceGet(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.get(ctx, name, opt)
+}
+
+// List will list all storager instances under this service.
+//
+// This function will create a context by default.
+func (s *Service) List(pairs ...Pair) (sti *StoragerIterator, err error) {
+	ctx := context.Background()
+	return s.ListWithContext(ctx, pairs...)
+}
+
+// ListWithContext will list all storager instances under this service.
+func (s *Service) ListWithContext(ctx context.Context, pairs ...Pair) (sti *StoragerIterator, err error) {
+	defer func() {
+		err = s.formatError("list", err, "")
+	}()
+
+	pairs = append(pairs, s.defaultPairs.List...)
+	var opt pairServiceList
+
+	opt, err = s.parsePairServiceList(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.list(ctx, opt)
+}
+
+var (
+	_ Appender    = &Storage{}
+	_ Copier      = &Storage{}
+	_ Direr       = &Storage{}
+	_ Fetcher     = &Storage{}
+	_ HTTPSigner  = &Storage{}
+	_ Linker      = &Storage{}
+	_ Mover       = &Storage{}
+	_ Multiparter = &Storage{}
+	_ Storager    = &Storage{}
+)
+
+type StorageFeatures struct {
+	// LoosePair loose_pair feature is designed for users who don't want strict pair checks.
+	//
+	// If this feature is enabled, the service will not return an error for not support pairs.
+	//
+	// This feature was introduced in GSP-109.
+	LoosePair bool
+	// VirtualDir virtual_dir feature is designed for a service that doesn't have native dir support but wants to provide simulated operations.
+	//
+	// - If this feature is disabled (the default behavior), the service will behave like it doesn't have any dir support.
+	// - If this feature is enabled, the service will support simulated dir behavior in create_dir, create, list, delete, and so on.
+	//
+	// This feature was introduced in GSP-109.
+	VirtualDir bool
+	// VirtualLink virtual_link feature is designed for a service that doesn't have native support for link.
+	//
+	// - If this feature is disabled (the default behavior), the service will only create native links.
+	// - If this feature is enabled, the service will simulate links with objects which carry the link target in user metadata.
+	//
+	// This feature was introduced in GSP-86.
+	VirtualLink bool
+}
+
+// Implements will return whether Storage implements the interface with given name, like "Appender".
+//
+// The Unimplemented structs are embedded into Storage, so type assertion always succeeds.
+func (s *Storage) Implements(name string) bool {
+	switch name {
+	case "Appender", "Copier", "Direr", "Fetcher", "HTTPSigner", "Linker", "Mover", "Multiparter", "Storager":
+		return true
+	default:
+		return false
+	}
+}
+
+// pairStorageNew is the parsed struct
+type pairStorageNew struct {
+	pairs []Pair
+
+	// Required pairs
+	HasName bool
+	Name    string
+	// Optional pairs
+	HasBandwidthLimit        bool
+	BandwidthLimit           int64
+	HasBufferPoolLimit       bool
+	BufferPoolLimit          int
+	HasCompression           bool
+	Compression              string
+	HasCompressionThreshold  bool
+	CompressionThreshold     int64
+	HasDefaultStoragePairs   bool
+	DefaultStoragePairs      DefaultStoragePairs
+	HasDryRun                bool
+	DryRun                   DryRunFunc
+	HasEncryptionKey         bool
+	EncryptionKey            EncryptionKey
+	HasEncryptionKeyResolver bool
+	EncryptionKeyResolver    EncryptionKeyResolver
+	HasPathMapper            bool
+	PathMapper               PathMapper
+	HasPathValidation        bool
+	PathValidation           string
+	HasReadOnly              bool
+	ReadOnly                 bool
+	HasShareSnapshot         bool
+	ShareSnapshot            string
+	HasStatCacheTTL          bool
+	StatCacheTTL             time.Duration
+	HasStorageFeatures       bool
+	StorageFeatures          StorageFeatures
+	HasWorkDir               bool
+	WorkDir                  string
+	// Enable features
+	hasEnableLoosePair   bool
+	EnableLoosePair      bool
+	hasEnableVirtualDir  bool
+	EnableVirtualDir     bool
+	hasEnableVirtualLink bool
+	EnableVirtualLink    bool
+	// Default pairs
+	hasDefaultAtomicWrite        bool
+	DefaultAtomicWrite           bool
+	hasDefaultCacheControl       bool
+	DefaultCacheControl          string
+	hasDefaultCheckQuota         bool
+	DefaultCheckQuota            bool
+	hasDefaultChunkSize          bool
+	DefaultChunkSize             int64
+	hasDefaultComputeContentMd5  bool
+	DefaultComputeContentMd5     bool
+	hasDefaultConcurrency        bool
+	DefaultConcurrency           int
+	hasDefaultContentDisposition bool
+	DefaultContentDisposition    string
+	hasDefaultContentEncoding    bool
+	DefaultContentEncoding       string
+	hasDefaultContentLanguage    bool
+	DefaultContentLanguage       string
+	hasDefaultCopySMBInfo        bool
+	DefaultCopySMBInfo           bool
+	hasDefaultCreateOnly         bool
+	DefaultCreateOnly            bool
+	hasDefaultCreateParents      bool
+	DefaultCreateParents         bool
+	hasDefaultFileAttributes     bool
+	DefaultFileAttributes        string
+	hasDefaultFilePermission     bool
+	DefaultFilePermission        string
+	hasDefaultFilePermissionKey  bool
+	DefaultFilePermissionKey     string
+	hasDefaultLeaseFiles         bool
+	DefaultLeaseFiles            bool
+	hasDefaultListExtendedInfo   bool
+	DefaultListExtendedInfo      bool
+	hasDefaultListPageSize       bool
+	DefaultListPageSize          int
+	hasDefaultPartSize           bool
+	DefaultPartSize              int64
+	hasDefaultPreserveFileInfo   bool
+	DefaultPreserveFileInfo      bool
+	hasDefaultReadRetries        bool
+	DefaultReadRetries           int
+	hasDefaultTimeout            bool
+	DefaultTimeout               time.Duration
+	hasDefaultTransactionalCRC64 bool
+	DefaultTransactionalCRC64    bool
+	hasDefaultUserMetadata       bool
+	DefaultUserMetadata          map[string]string
+	hasDefaultVerifyContentMd5   bool
+	DefaultVerifyContentMd5      bool
+	hasDefaultVerifyTransfer     bool
+	DefaultVerifyTransfer        bool
+}
+
+// parsePairStorageNew will parse Pair slice into *pairStorageNew
+func parsePairStorageNew(opts []Pair) (pairStorageNew, error) {
+	result := pairStorageNew{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		// Required pairs
+		case "name":
+			if result.HasName {
+				continue
+			}
+			result.HasName = true
+			result.Name = v.Value.(string)
+		// Optional pairs
+		case "bandwidth_limit":
+			if result.HasBandwidthLimit {
+				continue
+			}
+			result.HasBandwidthLimit = true
+			result.BandwidthLimit = v.Value.(int64)
+		case "buffer_pool_limit":
+			if result.HasBufferPoolLimit {
+				continue
+			}
+			result.HasBufferPoolLimit = true
+			result.BufferPoolLimit = v.Value.(int)
+		case "compression":
+			if result.HasCompression {
+				continue
+			}
+			result.HasCompression = true
+			result.Compression = v.Value.(string)
+		case "compression_threshold":
+			if result.HasCompressionThreshold {
+				continue
+			}
+			result.HasCompressionThreshold = true
+			result.CompressionThreshold = v.Value.(int64)
+		case "default_storage_pairs":
+			if result.HasDefaultStoragePairs {
+				continue
+			}
+			result.HasDefaultStoragePairs = true
+			result.DefaultStoragePairs = v.Value.(DefaultStoragePairs)
+		case "dry_run":
+			if result.HasDryRun {
+				continue
+			}
+			result.HasDryRun = true
+			result.DryRun = v.Value.(DryRunFunc)
+		case "encryption_key":
+			if result.HasEncryptionKey {
+				continue
+			}
+			result.HasEncryptionKey = true
+			result.EncryptionKey = v.Value.(EncryptionKey)
+		case "encryption_key_resolver":
+			if result.HasEncryptionKeyResolver {
+				continue
+			}
+			result.HasEncryptionKeyResolver = true
+			result.EncryptionKeyResolver = v.Value.(EncryptionKeyResolver)
+		case "path_mapper":
+			if result.HasPathMapper {
+				continue
+			}
+			result.HasPathMapper = true
+			result.PathMapper = v.Value.(PathMapper)
+		case "path_validation":
+			if result.HasPathValidation {
+				continue
+			}
+			result.HasPathValidation = true
+			result.PathValidation = v.Value.(string)
+		case "read_only":
+			if result.HasReadOnly {
+				continue
+			}
+			result.HasReadOnly = true
+			result.ReadOnly = v.Value.(bool)
+		case "share_snapshot":
+			if result.HasShareSnapshot {
+				continue
+			}
+			result.HasShareSnapshot = true
+			result.ShareSnapshot = v.Value.(string)
+		case "stat_cache_ttl":
+			if result.HasStatCacheTTL {
+				continue
+			}
+			result.HasStatCacheTTL = true
+			result.StatCacheTTL = v.Value.(time.Duration)
+		case "storage_features":
+			if result.HasStorageFeatures {
+				continue
+			}
+			result.HasStorageFeatures = true
+			result.StorageFeatures = v.Value.(StorageFeatures)
+		case "work_dir":
+			if result.HasWorkDir {
+				continue
+			}
+			result.HasWorkDir = true
+			result.WorkDir = v.Value.(string)
+			// Enable features
+		case "enable_loose_pair":
+			if result.hasEnableLoosePair {
+				continue
+			}
+			result.hasEnableLoosePair = true
+			result.EnableLoosePair = true
+		case "enable_virtual_dir":
+			if result.hasEnableVirtualDir {
+				continue
+			}
+			result.hasEnableVirtualDir = true
+			result.EnableVirtualDir = true
+		case "enable_virtual_link":
+			if result.hasEnableVirtualLink {
+				continue
+			}
+			result.hasEnableVirtualLink = true
+			result.EnableVirtualLink = true
+			// Default pairs
+		case "default_atomic_write":
+			if result.hasDefaultAtomicWrite {
+				continue
+			}
+			result.hasDefaultAtomicWrite = true
+			result.DefaultAtomicWrite = v.Value.(bool)
+		case "default_cache_control":
+			if result.hasDefaultCacheControl {
+				continue
+			}
+			result.hasDefaultCacheControl = true
+			result.DefaultCacheControl = v.Value.(string)
+		case "default_check_quota":
+			if result.hasDefaultCheckQuota {
+				continue
+			}
+			result.hasDefaultCheckQuota = true
+			result.DefaultCheckQuota = v.Value.(bool)
+		case "default_chunk_size":
+			if result.hasDefaultChunkSize {
+				continue
+			}
+			result.hasDefaultChunkSize = true
+			result.DefaultChunkSize = v.Value.(int64)
+		case "default_compute_content_md5":
+			if result.hasDefaultComputeContentMd5 {
+				continue
+			}
+			result.hasDefaultComputeContentMd5 = true
+			result.DefaultComputeContentMd5 = v.Value.(bool)
+		case "default_concurrency":
+			if result.hasDefaultConcurrency {
+				continue
+			}
+			result.hasDefaultConcurrency = true
+			result.DefaultConcurrency = v.Value.(int)
+		case "default_content_disposition":
+			if result.hasDefaultContentDisposition {
+				continue
+			}
+			result.hasDefaultContentDisposition = true
+			result.DefaultContentDisposition = v.Value.(string)
+		case "default_content_encoding":
+			if result.hasDefaultContentEncoding {
+				continue
+			}
+			result.hasDefaultContentEncoding = true
+			result.DefaultContentEncoding = v.Value.(string)
+		case "default_content_language":
+			if result.hasDefaultContentLanguage {
+				continue
+			}
+			result.hasDefaultContentLanguage = true
+			result.DefaultContentLanguage = v.Value.(string)
+		case "default_copy_smb_info":
+			if result.hasDefaultCopySMBInfo {
+				continue
+			}
+			result.hasDefaultCopySMBInfo = true
+			result.DefaultCopySMBInfo = v.Value.(bool)
+		case "default_create_only":
+			if result.hasDefaultCreateOnly {
+				continue
+			}
+			result.hasDefaultCreateOnly = true
+			result.DefaultCreateOnly = v.Value.(bool)
+		case "default_create_parents":
+			if result.hasDefaultCreateParents {
+				continue
+			}
+			result.hasDefaultCreateParents = true
+			result.DefaultCreateParents = v.Value.(bool)
+		case "default_file_attributes":
+			if result.hasDefaultFileAttributes {
+				continue
+			}
+			result.hasDefaultFileAttributes = true
+			result.DefaultFileAttributes = v.Value.(string)
+		case "default_file_permission":
+			if result.hasDefaultFilePermission {
+				continue
+			}
+			result.hasDefaultFilePermission = true
+			result.DefaultFilePermission = v.Value.(string)
+		case "default_file_permission_key":
+			if result.hasDefaultFilePermissionKey {
+				continue
+			}
+			result.hasDefaultFilePermissionKey = true
+			result.DefaultFilePermissionKey = v.Value.(string)
+		case "default_lease_files":
+			if result.hasDefaultLeaseFiles {
+				continue
+			}
+			result.hasDefaultLeaseFiles = true
+			result.DefaultLeaseFiles = v.Value.(bool)
+		case "default_list_extended_info":
+			if result.hasDefaultListExtendedInfo {
+				continue
+			}
+			result.hasDefaultListExtendedInfo = true
+			result.DefaultListExtendedInfo = v.Value.(bool)
+		case "default_list_page_size":
+			if result.hasDefaultListPageSize {
+				continue
+			}
+			result.hasDefaultListPageSize = true
+			result.DefaultListPageSize = v.Value.(int)
+		case "default_part_size":
+			if result.hasDefaultPartSize {
+				continue
+			}
+			result.hasDefaultPartSize = true
+			result.DefaultPartSize = v.Value.(int64)
+		case "default_preserve_file_info":
+			if result.hasDefaultPreserveFileInfo {
+				continue
+			}
+			result.hasDefaultPreserveFileInfo = true
+			result.DefaultPreserveFileInfo = v.Value.(bool)
+		case "default_read_retries":
+			if result.hasDefaultReadRetries {
+				continue
+			}
+			result.hasDefaultReadRetries = true
+			result.DefaultReadRetries = v.Value.(int)
+		case "default_timeout":
+			if result.hasDefaultTimeout {
+				continue
+			}
+			result.hasDefaultTimeout = true
+			result.DefaultTimeout = v.Value.(time.Duration)
+		case "default_transactional_crc64":
+			if result.hasDefaultTransactionalCRC64 {
+				continue
+			}
+			result.hasDefaultTransactionalCRC64 = true
+			result.DefaultTransactionalCRC64 = v.Value.(bool)
+		case "default_user_metadata":
+			if result.hasDefaultUserMetadata {
+				continue
+			}
+			result.hasDefaultUserMetadata = true
+			result.DefaultUserMetadata = v.Value.(map[string]string)
+		case "default_verify_content_md5":
+			if result.hasDefaultVerifyContentMd5 {
+				continue
+			}
+			result.hasDefaultVerifyContentMd5 = true
+			result.DefaultVerifyContentMd5 = v.Value.(bool)
+		case "default_verify_transfer":
+			if result.hasDefaultVerifyTransfer {
+				continue
+			}
+			result.hasDefaultVerifyTransfer = true
+			result.DefaultVerifyTransfer = v.Value.(bool)
+		}
+	}
+
+	// Enable features
+	if result.hasEnableLoosePair {
+		result.HasStorageFeatures = true
+		result.StorageFeatures.LoosePair = true
+	}
+	if result.hasEnableVirtualDir {
+		result.HasStorageFeatures = true
+		result.StorageFeatures.VirtualDir = true
+	}
+	if result.hasEnableVirtualLink {
+		result.HasStorageFeatures = true
+		result.StorageFeatures.VirtualLink = true
+	}
+
+	// Default pairs
+	if result.hasDefaultAtomicWrite {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithAtomicWrite(result.DefaultAtomicWrite))
+	}
+	if result.hasDefaultCacheControl {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateAppend = append(result.DefaultStoragePairs.CreateAppend, WithCacheControl(result.DefaultCacheControl))
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithCacheControl(result.DefaultCacheControl))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithCacheControl(result.DefaultCacheControl))
+	}
+	if result.hasDefaultCheckQuota {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithCheckQuota(result.DefaultCheckQuota))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithCheckQuota(result.DefaultCheckQuota))
+	}
+	if result.hasDefaultChunkSize {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Read = append(result.DefaultStoragePairs.Read, WithChunkSize(result.DefaultChunkSize))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithChunkSize(result.DefaultChunkSize))
+		result.DefaultStoragePairs.WriteAppend = append(result.DefaultStoragePairs.WriteAppend, WithChunkSize(result.DefaultChunkSize))
+		result.DefaultStoragePairs.WriteMultipart = append(result.DefaultStoragePairs.WriteMultipart, WithChunkSize(result.DefaultChunkSize))
+	}
+	if result.hasDefaultComputeContentMd5 {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithComputeContentMd5(result.DefaultComputeContentMd5))
+	}
+	if result.hasDefaultConcurrency {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.Delete = append(result.DefaultStoragePairs.Delete, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.List = append(result.DefaultStoragePairs.List, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.Read = append(result.DefaultStoragePairs.Read, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.WriteAppend = append(result.DefaultStoragePairs.WriteAppend, WithConcurrency(result.DefaultConcurrency))
+		result.DefaultStoragePairs.WriteMultipart = append(result.DefaultStoragePairs.WriteMultipart, WithConcurrency(result.DefaultConcurrency))
+	}
+	if result.hasDefaultContentDisposition {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateAppend = append(result.DefaultStoragePairs.CreateAppend, WithContentDisposition(result.DefaultContentDisposition))
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithContentDisposition(result.DefaultContentDisposition))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithContentDisposition(result.DefaultContentDisposition))
+	}
+	if result.hasDefaultContentEncoding {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateAppend = append(result.DefaultStoragePairs.CreateAppend, WithContentEncoding(result.DefaultContentEncoding))
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithContentEncoding(result.DefaultContentEncoding))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithContentEncoding(result.DefaultContentEncoding))
+	}
+	if result.hasDefaultContentLanguage {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateAppend = append(result.DefaultStoragePairs.CreateAppend, WithContentLanguage(result.DefaultContentLanguage))
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithContentLanguage(result.DefaultContentLanguage))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithContentLanguage(result.DefaultContentLanguage))
+	}
+	if result.hasDefaultCopySMBInfo {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithCopySMBInfo(result.DefaultCopySMBInfo))
+		result.DefaultStoragePairs.Fetch = append(result.DefaultStoragePairs.Fetch, WithCopySMBInfo(result.DefaultCopySMBInfo))
+	}
+	if result.hasDefaultCreateOnly {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithCreateOnly(result.DefaultCreateOnly))
+	}
+	if result.hasDefaultCreateParents {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateDir = append(result.DefaultStoragePairs.CreateDir, WithCreateParents(result.DefaultCreateParents))
+	}
+	if result.hasDefaultFileAttributes {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithFileAttributes(result.DefaultFileAttributes))
+		result.DefaultStoragePairs.CreateDir = append(result.DefaultStoragePairs.CreateDir, WithFileAttributes(result.DefaultFileAttributes))
+		result.DefaultStoragePairs.Fetch = append(result.DefaultStoragePairs.Fetch, WithFileAttributes(result.DefaultFileAttributes))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithFileAttributes(result.DefaultFileAttributes))
+	}
+	if result.hasDefaultFilePermission {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithFilePermission(result.DefaultFilePermission))
+		result.DefaultStoragePairs.CreateDir = append(result.DefaultStoragePairs.CreateDir, WithFilePermission(result.DefaultFilePermission))
+		result.DefaultStoragePairs.Fetch = append(result.DefaultStoragePairs.Fetch, WithFilePermission(result.DefaultFilePermission))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithFilePermission(result.DefaultFilePermission))
+	}
+	if result.hasDefaultFilePermissionKey {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithFilePermissionKey(result.DefaultFilePermissionKey))
+		result.DefaultStoragePairs.CreateDir = append(result.DefaultStoragePairs.CreateDir, WithFilePermissionKey(result.DefaultFilePermissionKey))
+		result.DefaultStoragePairs.Fetch = append(result.DefaultStoragePairs.Fetch, WithFilePermissionKey(result.DefaultFilePermissionKey))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithFilePermissionKey(result.DefaultFilePermissionKey))
+	}
+	if result.hasDefaultLeaseFiles {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithLeaseFiles(result.DefaultLeaseFiles))
+		result.DefaultStoragePairs.Delete = append(result.DefaultStoragePairs.Delete, WithLeaseFiles(result.DefaultLeaseFiles))
+	}
+	if result.hasDefaultListExtendedInfo {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.List = append(result.DefaultStoragePairs.List, WithListExtendedInfo(result.DefaultListExtendedInfo))
+	}
+	if result.hasDefaultListPageSize {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.List = append(result.DefaultStoragePairs.List, WithListPageSize(result.DefaultListPageSize))
+	}
+	if result.hasDefaultPartSize {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateMultipart = append(result.DefaultStoragePairs.CreateMultipart, WithPartSize(result.DefaultPartSize))
+	}
+	if result.hasDefaultPreserveFileInfo {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithPreserveFileInfo(result.DefaultPreserveFileInfo))
+	}
+	if result.hasDefaultReadRetries {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Read = append(result.DefaultStoragePairs.Read, WithReadRetries(result.DefaultReadRetries))
+	}
+	if result.hasDefaultTimeout {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Delete = append(result.DefaultStoragePairs.Delete, WithTimeout(result.DefaultTimeout))
+		result.DefaultStoragePairs.List = append(result.DefaultStoragePairs.List, WithTimeout(result.DefaultTimeout))
+		result.DefaultStoragePairs.Metadata = append(result.DefaultStoragePairs.Metadata, WithTimeout(result.DefaultTimeout))
+		result.DefaultStoragePairs.Read = append(result.DefaultStoragePairs.Read, WithTimeout(result.DefaultTimeout))
+		result.DefaultStoragePairs.Stat = append(result.DefaultStoragePairs.Stat, WithTimeout(result.DefaultTimeout))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithTimeout(result.DefaultTimeout))
+	}
+	if result.hasDefaultTransactionalCRC64 {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithTransactionalCRC64(result.DefaultTransactionalCRC64))
+		result.DefaultStoragePairs.WriteAppend = append(result.DefaultStoragePairs.WriteAppend, WithTransactionalCRC64(result.DefaultTransactionalCRC64))
+		result.DefaultStoragePairs.WriteMultipart = append(result.DefaultStoragePairs.WriteMultipart, WithTransactionalCRC64(result.DefaultTransactionalCRC64))
+	}
+	if result.hasDefaultUserMetadata {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.CreateDir = append(result.DefaultStoragePairs.CreateDir, WithUserMetadata(result.DefaultUserMetadata))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithUserMetadata(result.DefaultUserMetadata))
+	}
+	if result.hasDefaultVerifyContentMd5 {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Read = append(result.DefaultStoragePairs.Read, WithVerifyContentMd5(result.DefaultVerifyContentMd5))
+	}
+	if result.hasDefaultVerifyTransfer {
+		result.HasDefaultStoragePairs = true
+		result.DefaultStoragePairs.Copy = append(result.DefaultStoragePairs.Copy, WithVerifyTransfer(result.DefaultVerifyTransfer))
+		result.DefaultStoragePairs.Write = append(result.DefaultStoragePairs.Write, WithVerifyTransfer(result.DefaultVerifyTransfer))
+	}
+
+	if !result.HasName {
+		return pairStorageNew{}, services.PairRequiredError{Keys: []string{"name"}}
+	}
+
+	return result, nil
+}
+
+// DefaultStoragePairs is default pairs for specific action
+type DefaultStoragePairs struct {
+	CommitAppend      []Pair
+	CompleteMultipart []Pair
+	Copy              []Pair
+	Create            []Pair
+	CreateAppend      []Pair
+	CreateDir         []Pair
+	CreateLink        []Pair
+	CreateMultipart   []Pair
+	Delete            []Pair
+	Fetch             []Pair
+	List              []Pair
+	ListMultipart     []Pair
+	Metadata          []Pair
+	Move              []Pair
+	QuerySignHTTP     []Pair
+	Read              []Pair
+	Stat              []Pair
+	Write             []Pair
+	WriteAppend       []Pair
+	WriteMultipart    []Pair
+}
+
+// pairStorageCommitAppend is the parsed struct
+type pairStorageCommitAppend struct {
+	pairs []Pair
+}
+
+// parsePairStorageCommitAppend will parse Pair slice into *pairStorageCommitAppend
+func (s *Storage) parsePairStorageCommitAppend(opts []Pair) (pairStorageCommitAppend, error) {
+	result := pairStorageCommitAppend{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCommitAppend{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCompleteMultipart is the parsed struct
+type pairStorageCompleteMultipart struct {
+	pairs []Pair
+}
+
+// parsePairStorageCompleteMultipart will parse Pair slice into *pairStorageCompleteMultipart
+func (s *Storage) parsePairStorageCompleteMultipart(opts []Pair) (pairStorageCompleteMultipart, error) {
+	result := pairStorageCompleteMultipart{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCompleteMultipart{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCopy is the parsed struct
+type pairStorageCopy struct {
+	pairs                []Pair
+	HasCallOptions       bool
+	CallOptions          CallOptions
+	HasClientRequestID   bool
+	ClientRequestID      string
+	HasConcurrency       bool
+	Concurrency          int
+	HasCopyProgress      bool
+	CopyProgress         CopyProgressFunc
+	HasCopySMBInfo       bool
+	CopySMBInfo          bool
+	HasFileAttributes    bool
+	FileAttributes       string
+	HasFilePermission    bool
+	FilePermission       string
+	HasFilePermissionKey bool
+	FilePermissionKey    string
+	HasLeaseFiles        bool
+	LeaseFiles           bool
+	HasProgress          bool
+	Progress             ProgressFunc
+	HasSourceShare       bool
+	SourceShare          string
+	HasVerifyTransfer    bool
+	VerifyTransfer       bool
+}
+
+// parsePairStorageCopy will parse Pair slice into *pairStorageCopy
+func (s *Storage) parsePairStorageCopy(opts []Pair) (pairStorageCopy, error) {
+	result := pairStorageCopy{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "copy_progress":
+			if result.HasCopyProgress {
+				continue
+			}
+			result.HasCopyProgress = true
+			result.CopyProgress = v.Value.(CopyProgressFunc)
+			continue
+		case "copy_smb_info":
+			if result.HasCopySMBInfo {
+				continue
+			}
+			result.HasCopySMBInfo = true
+			result.CopySMBInfo = v.Value.(bool)
+			continue
+		case "file_attributes":
+			if result.HasFileAttributes {
+				continue
+			}
+			result.HasFileAttributes = true
+			result.FileAttributes = v.Value.(string)
+			continue
+		case "file_permission":
+			if result.HasFilePermission {
+				continue
+			}
+			result.HasFilePermission = true
+			result.FilePermission = v.Value.(string)
+			continue
+		case "file_permission_key":
+			if result.HasFilePermissionKey {
+				continue
+			}
+			result.HasFilePermissionKey = true
+			result.FilePermissionKey = v.Value.(string)
+			continue
+		case "lease_files":
+			if result.HasLeaseFiles {
+				continue
+			}
+			result.HasLeaseFiles = true
+			result.LeaseFiles = v.Value.(bool)
+			continue
+		case "progress":
+			if result.HasProgress {
+				continue
+			}
+			result.HasProgress = true
+			result.Progress = v.Value.(ProgressFunc)
+			continue
+		case "source_share":
+			if result.HasSourceShare {
+				continue
+			}
+			result.HasSourceShare = true
+			result.SourceShare = v.Value.(string)
+			continue
+		case "verify_transfer":
+			if result.HasVerifyTransfer {
+				continue
+			}
+			result.HasVerifyTransfer = true
+			result.VerifyTransfer = v.Value.(bool)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCopy{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCreate is the parsed struct
+type pairStorageCreate struct {
+	pairs         []Pair
+	HasObjectMode bool
+	ObjectMode    ObjectMode
+}
+
+// parsePairStorageCreate will parse Pair slice into *pairStorageCreate
+func (s *Storage) parsePairStorageCreate(opts []Pair) (pairStorageCreate, error) {
+	result := pairStorageCreate{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "object_mode":
+			if result.HasObjectMode {
+				continue
+			}
+			result.HasObjectMode = true
+			result.ObjectMode = v.Value.(ObjectMode)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCreate{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCreateAppend is the parsed struct
+type pairStorageCreateAppend struct {
+	pairs                 []Pair
+	HasCacheControl       bool
+	CacheControl          string
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasContentDisposition bool
+	ContentDisposition    string
+	HasContentEncoding    bool
+	ContentEncoding       string
+	HasContentLanguage    bool
+	ContentLanguage       string
+	HasContentType        bool
+	ContentType           string
+}
+
+// parsePairStorageCreateAppend will parse Pair slice into *pairStorageCreateAppend
+func (s *Storage) parsePairStorageCreateAppend(opts []Pair) (pairStorageCreateAppend, error) {
+	result := pairStorageCreateAppend{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "cache_control":
+			if result.HasCacheControl {
+				continue
+			}
+			result.HasCacheControl = true
+			result.CacheControl = v.Value.(string)
+			continue
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "content_disposition":
+			if result.HasContentDisposition {
+				continue
+			}
+			result.HasContentDisposition = true
+			result.ContentDisposition = v.Value.(string)
+			continue
+		case "content_encoding":
+			if result.HasContentEncoding {
+				continue
+			}
+			result.HasContentEncoding = true
+			result.ContentEncoding = v.Value.(string)
+			continue
+		case "content_language":
+			if result.HasContentLanguage {
+				continue
+			}
+			result.HasContentLanguage = true
+			result.ContentLanguage = v.Value.(string)
+			continue
+		case "content_type":
+			if result.HasContentType {
+				continue
+			}
+			result.HasContentType = true
+			result.ContentType = v.Value.(string)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCreateAppend{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCreateDir is the parsed struct
+type pairStorageCreateDir struct {
+	pairs                []Pair
+	HasCallOptions       bool
+	CallOptions          CallOptions
+	HasClientRequestID   bool
+	ClientRequestID      string
+	HasCreateParents     bool
+	CreateParents        bool
+	HasFileAttributes    bool
+	FileAttributes       string
+	HasFileCreationTime  bool
+	FileCreationTime     time.Time
+	HasFileLastWriteTime bool
+	FileLastWriteTime    time.Time
+	HasFilePermission    bool
+	FilePermission       string
+	HasFilePermissionKey bool
+	FilePermissionKey    string
+	HasUserMetadata      bool
+	UserMetadata         map[string]string
+}
+
+// parsePairStorageCreateDir will parse Pair slice into *pairStorageCreateDir
+func (s *Storage) parsePairStorageCreateDir(opts []Pair) (pairStorageCreateDir, error) {
+	result := pairStorageCreateDir{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "create_parents":
+			if result.HasCreateParents {
+				continue
+			}
+			result.HasCreateParents = true
+			result.CreateParents = v.Value.(bool)
+			continue
+		case "file_attributes":
+			if result.HasFileAttributes {
+				continue
+			}
+			result.HasFileAttributes = true
+			result.FileAttributes = v.Value.(string)
+			continue
+		case "file_creation_time":
+			if result.HasFileCreationTime {
+				continue
+			}
+			result.HasFileCreationTime = true
+			result.FileCreationTime = v.Value.(time.Time)
+			continue
+		case "file_last_write_time":
+			if result.HasFileLastWriteTime {
+				continue
+			}
+			result.HasFileLastWriteTime = true
+			result.FileLastWriteTime = v.Value.(time.Time)
+			continue
+		case "file_permission":
+			if result.HasFilePermission {
+				continue
+			}
+			result.HasFilePermission = true
+			result.FilePermission = v.Value.(string)
+			continue
+		case "file_permission_key":
+			if result.HasFilePermissionKey {
+				continue
+			}
+			result.HasFilePermissionKey = true
+			result.FilePermissionKey = v.Value.(string)
+			continue
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCreateDir{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCreateLink is the parsed struct
+type pairStorageCreateLink struct {
+	pairs              []Pair
+	HasCallOptions     bool
+	CallOptions        CallOptions
+	HasClientRequestID bool
+	ClientRequestID    string
+	HasHardLink        bool
+	HardLink           bool
+}
+
+// parsePairStorageCreateLink will parse Pair slice into *pairStorageCreateLink
+func (s *Storage) parsePairStorageCreateLink(opts []Pair) (pairStorageCreateLink, error) {
+	result := pairStorageCreateLink{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "hard_link":
+			if result.HasHardLink {
+				continue
+			}
+			result.HasHardLink = true
+			result.HardLink = v.Value.(bool)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCreateLink{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
+}
+
+// pairStorageCreateMultipart is the parsed struct
+type pairStorageCreateMultipart struct {
+	pairs                 []Pair
+	HasCacheControl       bool
+	CacheControl          string
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasCheckQuota         bool
+	CheckQuota            bool
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasContentDisposition bool
+	ContentDisposition    string
+	HasContentEncoding    bool
+	ContentEncoding       string
+	HasContentLanguage    bool
+	ContentLanguage       string
+	HasContentType        bool
+	ContentType           string
+	HasPartSize           bool
+	PartSize              int64
+	HasSize               bool
+	Size                  int64
+}
+
+// parsePairStorageCreateMultipart will parse Pair slice into *pairStorageCreateMultipart
+func (s *Storage) parsePairStorageCreateMultipart(opts []Pair) (pairStorageCreateMultipart, error) {
+	result := pairStorageCreateMultipart{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "cache_control":
+			if result.HasCacheControl {
+				continue
+			}
+			result.HasCacheControl = true
+			result.CacheControl = v.Value.(string)
+			continue
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "check_quota":
+			if result.HasCheckQuota {
+				continue
+			}
+			result.HasCheckQuota = true
+			result.CheckQuota = v.Value.(bool)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "content_disposition":
+			if result.HasContentDisposition {
+				continue
+			}
+			result.HasContentDisposition = true
+			result.ContentDisposition = v.Value.(string)
+			continue
+		case "content_encoding":
+			if result.HasContentEncoding {
+				continue
+			}
+			result.HasContentEncoding = true
+			result.ContentEncoding = v.Value.(string)
+			continue
+		case "content_language":
+			if result.HasContentLanguage {
+				continue
+			}
+			result.HasContentLanguage = true
+			result.ContentLanguage = v.Value.(string)
+			continue
+		case "content_type":
+			if result.HasContentType {
+				continue
+			}
+			result.HasContentType = true
+			result.ContentType = v.Value.(string)
+			continue
+		case "part_size":
+			if result.HasPartSize {
+				continue
+			}
+			result.HasPartSize = true
+			result.PartSize = v.Value.(int64)
+			continue
+		case "size":
+			if result.HasSize {
+				continue
+			}
+			result.HasSize = true
+			result.Size = v.Value.(int64)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageCreateMultipart{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+	if !result.HasSize {
+		return pairStorageCreateMultipart{}, services.PairRequiredError{Keys: []string{"size"}}
+	}
+
+	return result, nil
+}
+
+// pairStorageDelete is the parsed struct
+type pairStorageDelete struct {
+	pairs                []Pair
+	HasCallOptions       bool
+	CallOptions          CallOptions
+	HasClientRequestID   bool
+	ClientRequestID      string
+	HasConcurrency       bool
+	Concurrency          int
+	HasIfMatch           bool
+	IfMatch              string
+	HasIfModifiedSince   bool
+	IfModifiedSince      time.Time
+	HasIfNoneMatch       bool
+	IfNoneMatch          string
+	HasIfUnmodifiedSince bool
+	IfUnmodifiedSince    time.Time
+	HasLeaseFiles        bool
+	LeaseFiles           bool
+	HasLeaseID           bool
+	LeaseID              string
+	HasObjectMode        bool
+	ObjectMode           ObjectMode
+	HasProgress          bool
+	Progress             ProgressFunc
+	HasTimeout           bool
+	Timeout              time.Duration
+}
+
+// parsePairStorageDelete will parse Pair slice into *pairStorageDelete
+func (s *Storage) parsePairStorageDelete(opts []Pair) (pairStorageDelete, error) {
+	result := pairStorageDelete{
+		pairs: opts,
+	}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+			continue
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+			continue
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+			continue
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
+			continue
+		case "lease_files":
+			if result.HasLeaseFiles {
+				continue
+			}
+			result.HasLeaseFiles = true
+			result.LeaseFiles = v.Value.(bool)
+			continue
+		case "lease_id":
+			if result.HasLeaseID {
+				continue
+			}
+			result.HasLeaseID = true
+			result.LeaseID = v.Value.(string)
+			continue
+		case "object_mode":
+			if result.HasObjectMode {
+				continue
+			}
+			result.HasObjectMode = true
+			result.ObjectMode = v.Value.(ObjectMode)
+			continue
+		case "progress":
+			if result.HasProgress {
+				continue
+			}
+			result.HasProgress = true
+			result.Progress = v.Value.(ProgressFunc)
+			continue
+		case "timeout":
+			if result.HasTimeout {
+				continue
+			}
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
 
-// WithStorageFeatures will apply storage_features value to Options.
-//
-// StorageFeatures set storage features
-func WithStorageFeatures(v StorageFeatures) Pair {
-	return Pair{
-		Key:   "storage_features",
-		Value: v,
-	}
-}
+	// Check required pairs.
 
-var pairMap = map[string]string{
-	"content_md5":           "string",
-	"content_type":          "string",
-	"context":               "context.Context",
-	"continuation_token":    "string",
-	"credential":            "string",
-	"default_storage_pairs": "DefaultStoragePairs",
-	"endpoint":              "string",
-	"expire":                "time.Duration",
-	"http_client_options":   "*httpclient.Options",
-	"interceptor":           "Interceptor",
-	"io_callback":           "func([]byte)",
-	"list_mode":             "ListMode",
-	"location":              "string",
-	"multipart_id":          "string",
-	"name":                  "string",
-	"object_mode":           "ObjectMode",
-	"offset":                "int64",
-	"size":                  "int64",
-	"storage_features":      "StorageFeatures",
-	"work_dir":              "string",
+	return result, nil
 }
-var (
-	_ Direr    = &Storage{}
-	_ Storager = &Storage{}
-)
 
-type StorageFeatures struct {
+// pairStorageFetch is the parsed struct
+type pairStorageFetch struct {
+	pairs                []Pair
+	HasCallOptions       bool
+	CallOptions          CallOptions
+	HasClientRequestID   bool
+	ClientRequestID      string
+	HasCopyProgress      bool
+	CopyProgress         CopyProgressFunc
+	HasCopySMBInfo       bool
+	CopySMBInfo          bool
+	HasFileAttributes    bool
+	FileAttributes       string
+	HasFilePermission    bool
+	FilePermission       string
+	HasFilePermissionKey bool
+	FilePermissionKey    string
+	HasProgress          bool
+	Progress             ProgressFunc
 }
 
-// pairStorageNew is the parsed struct
-type pairStorageNew struct {
-	pairs []Pair
+// parsePairStorageFetch will parse Pair slice into *pairStorageFetch
+func (s *Storage) parsePairStorageFetch(opts []Pair) (pairStorageFetch, error) {
+	result := pairStorageFetch{
+		pairs: opts,
+	}
 
-	// Required pairs
-	HasCredential bool
-	Credential    string
-	HasEndpoint   bool
-	Endpoint      string
-	HasName       bool
-	Name          string
-	// Optional pairs
-	HasDefaultStoragePairs bool
-	DefaultStoragePairs    DefaultStoragePairs
-	HasStorageFeatures     bool
-	StorageFeatures        StorageFeatures
-	HasWorkDir             bool
-	WorkDir                string
-	// Enable features
-	// Default pairs
+	for _, v := range opts {
+		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "copy_progress":
+			if result.HasCopyProgress {
+				continue
+			}
+			result.HasCopyProgress = true
+			result.CopyProgress = v.Value.(CopyProgressFunc)
+			continue
+		case "copy_smb_info":
+			if result.HasCopySMBInfo {
+				continue
+			}
+			result.HasCopySMBInfo = true
+			result.CopySMBInfo = v.Value.(bool)
+			continue
+		case "file_attributes":
+			if result.HasFileAttributes {
+				continue
+			}
+			result.HasFileAttributes = true
+			result.FileAttributes = v.Value.(string)
+			continue
+		case "file_permission":
+			if result.HasFilePermission {
+				continue
+			}
+			result.HasFilePermission = true
+			result.FilePermission = v.Value.(string)
+			continue
+		case "file_permission_key":
+			if result.HasFilePermissionKey {
+				continue
+			}
+			result.HasFilePermissionKey = true
+			result.FilePermissionKey = v.Value.(string)
+			continue
+		case "progress":
+			if result.HasProgress {
+				continue
+			}
+			result.HasProgress = true
+			result.Progress = v.Value.(ProgressFunc)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageFetch{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	// Check required pairs.
+
+	return result, nil
 }
 
-// parsePairStorageNew will parse Pair slice into *pairStorageNew
-func parsePairStorageNew(opts []Pair) (pairStorageNew, error) {
-	result := pairStorageNew{
+// pairStorageList is the parsed struct
+type pairStorageList struct {
+	pairs                 []Pair
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasConcurrency        bool
+	Concurrency           int
+	HasContinuationToken  bool
+	ContinuationToken     string
+	HasListExtendedInfo   bool
+	ListExtendedInfo      bool
+	HasListMaxSize        bool
+	ListMaxSize           int64
+	HasListMinSize        bool
+	ListMinSize           int64
+	HasListMode           bool
+	ListMode              ListMode
+	HasListModifiedAfter  bool
+	ListModifiedAfter     time.Time
+	HasListModifiedBefore bool
+	ListModifiedBefore    time.Time
+	HasListNameRegexp     bool
+	ListNameRegexp        string
+	HasListNameSuffix     bool
+	ListNameSuffix        string
+	HasListPageSize       bool
+	ListPageSize          int
+	HasProgress           bool
+	Progress              ProgressFunc
+	HasTimeout            bool
+	Timeout               time.Duration
+}
+
+// parsePairStorageList will parse Pair slice into *pairStorageList
+func (s *Storage) parsePairStorageList(opts []Pair) (pairStorageList, error) {
+	result := pairStorageList{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		// Required pairs
-		case "credential":
-			if result.HasCredential {
+		case "call_options":
+			if result.HasCallOptions {
 				continue
 			}
-			result.HasCredential = true
-			result.Credential = v.Value.(string)
-		case "endpoint":
-			if result.HasEndpoint {
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
 				continue
 			}
-			result.HasEndpoint = true
-			result.Endpoint = v.Value.(string)
-		case "name":
-			if result.HasName {
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
 				continue
 			}
-			result.HasName = true
-			result.Name = v.Value.(string)
-		// Optional pairs
-		case "default_storage_pairs":
-			if result.HasDefaultStoragePairs {
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "continuation_token":
+			if result.HasContinuationToken {
 				continue
 			}
-			result.HasDefaultStoragePairs = true
-			result.DefaultStoragePairs = v.Value.(DefaultStoragePairs)
-		case "storage_features":
-			if result.HasStorageFeatures {
+			result.HasContinuationToken = true
+			result.ContinuationToken = v.Value.(string)
+			continue
+		case "list_extended_info":
+			if result.HasListExtendedInfo {
 				continue
 			}
-			result.HasStorageFeatures = true
-			result.StorageFeatures = v.Value.(StorageFeatures)
-		case "work_dir":
-			if result.HasWorkDir {
+			result.HasListExtendedInfo = true
+			result.ListExtendedInfo = v.Value.(bool)
+			continue
+		case "list_max_size":
+			if result.HasListMaxSize {
 				continue
 			}
-			result.HasWorkDir = true
-			result.WorkDir = v.Value.(string)
-			// Enable features
-			// Default pairs
+			result.HasListMaxSize = true
+			result.ListMaxSize = v.Value.(int64)
+			continue
+		case "list_min_size":
+			if result.HasListMinSize {
+				continue
+			}
+			result.HasListMinSize = true
+			result.ListMinSize = v.Value.(int64)
+			continue
+		case "list_mode":
+			if result.HasListMode {
+				continue
+			}
+			result.HasListMode = true
+			result.ListMode = v.Value.(ListMode)
+			continue
+		case "list_modified_after":
+			if result.HasListModifiedAfter {
+				continue
+			}
+			result.HasListModifiedAfter = true
+			result.ListModifiedAfter = v.Value.(time.Time)
+			continue
+		case "list_modified_before":
+			if result.HasListModifiedBefore {
+				continue
+			}
+			result.HasListModifiedBefore = true
+			result.ListModifiedBefore = v.Value.(time.Time)
+			continue
+		case "list_name_regexp":
+			if result.HasListNameRegexp {
+				continue
+			}
+			result.HasListNameRegexp = true
+			result.ListNameRegexp = v.Value.(string)
+			continue
+		case "list_name_suffix":
+			if result.HasListNameSuffix {
+				continue
+			}
+			result.HasListNameSuffix = true
+			result.ListNameSuffix = v.Value.(string)
+			continue
+		case "list_page_size":
+			if result.HasListPageSize {
+				continue
+			}
+			result.HasListPageSize = true
+			result.ListPageSize = v.Value.(int)
+			continue
+		case "progress":
+			if result.HasProgress {
+				continue
+			}
+			result.HasProgress = true
+			result.Progress = v.Value.(ProgressFunc)
+			continue
+		case "timeout":
+			if result.HasTimeout {
+				continue
+			}
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
+			continue
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageList{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
-	// Enable features
+	// Check required pairs.
 
-	// Default pairs
+	return result, nil
+}
 
-	if !result.HasCredential {
-		return pairStorageNew{}, services.PairRequiredError{Keys: []string{"credential"}}
-	}
-	if !result.HasEndpoint {
-		return pairStorageNew{}, services.PairRequiredError{Keys: []string{"endpoint"}}
+// pairStorageListMultipart is the parsed struct
+type pairStorageListMultipart struct {
+	pairs []Pair
+}
+
+// parsePairStorageListMultipart will parse Pair slice into *pairStorageListMultipart
+func (s *Storage) parsePairStorageListMultipart(opts []Pair) (pairStorageListMultipart, error) {
+	result := pairStorageListMultipart{
+		pairs: opts,
 	}
-	if !result.HasName {
-		return pairStorageNew{}, services.PairRequiredError{Keys: []string{"name"}}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageListMultipart{}, services.PairUnsupportedError{Pair: v}
+		}
 	}
 
-	return result, nil
-}
+	// Check required pairs.
 
-// DefaultStoragePairs is default pairs for specific action
-type DefaultStoragePairs struct {
-	Create    []Pair
-	CreateDir []Pair
-	Delete    []Pair
-	List      []Pair
-	Metadata  []Pair
-	Read      []Pair
-	Stat      []Pair
-	Write     []Pair
+	return result, nil
 }
 
-// pairStorageCreate is the parsed struct
-type pairStorageCreate struct {
-	pairs         []Pair
-	HasObjectMode bool
-	ObjectMode    ObjectMode
+// pairStorageMetadata is the parsed struct
+type pairStorageMetadata struct {
+	pairs      []Pair
+	HasTimeout bool
+	Timeout    time.Duration
 }
 
-// parsePairStorageCreate will parse Pair slice into *pairStorageCreate
-func (s *Storage) parsePairStorageCreate(opts []Pair) (pairStorageCreate, error) {
-	result := pairStorageCreate{
+// parsePairStorageMetadata will parse Pair slice into *pairStorageMetadata
+func (s *Storage) parsePairStorageMetadata(opts []Pair) (pairStorageMetadata, error) {
+	result := pairStorageMetadata{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		case "object_mode":
-			if result.HasObjectMode {
+		case "timeout":
+			if result.HasTimeout {
 				continue
 			}
-			result.HasObjectMode = true
-			result.ObjectMode = v.Value.(ObjectMode)
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
 			continue
 		default:
-			return pairStorageCreate{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageMetadata{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -254,21 +3499,51 @@ func (s *Storage) parsePairStorageCreate(opts []Pair) (pairStorageCreate, error)
 	return result, nil
 }
 
-// pairStorageCreateDir is the parsed struct
-type pairStorageCreateDir struct {
-	pairs []Pair
+// pairStorageMove is the parsed struct
+type pairStorageMove struct {
+	pairs              []Pair
+	HasCallOptions     bool
+	CallOptions        CallOptions
+	HasClientRequestID bool
+	ClientRequestID    string
+	HasObjectMode      bool
+	ObjectMode         ObjectMode
 }
 
-// parsePairStorageCreateDir will parse Pair slice into *pairStorageCreateDir
-func (s *Storage) parsePairStorageCreateDir(opts []Pair) (pairStorageCreateDir, error) {
-	result := pairStorageCreateDir{
+// parsePairStorageMove will parse Pair slice into *pairStorageMove
+func (s *Storage) parsePairStorageMove(opts []Pair) (pairStorageMove, error) {
+	result := pairStorageMove{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "object_mode":
+			if result.HasObjectMode {
+				continue
+			}
+			result.HasObjectMode = true
+			result.ObjectMode = v.Value.(ObjectMode)
+			continue
 		default:
-			return pairStorageCreateDir{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageMove{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -277,30 +3552,33 @@ func (s *Storage) parsePairStorageCreateDir(opts []Pair) (pairStorageCreateDir,
 	return result, nil
 }
 
-// pairStorageDelete is the parsed struct
-type pairStorageDelete struct {
-	pairs         []Pair
-	HasObjectMode bool
-	ObjectMode    ObjectMode
-}
-
-// parsePairStorageDelete will parse Pair slice into *pairStorageDelete
-func (s *Storage) parsePairStorageDelete(opts []Pair) (pairStorageDelete, error) {
-	result := pairStorageDelete{
+// pairStorageQuerySignHTTP is the parsed struct
+type pairStorageQuerySignHTTP struct {
+	pairs   []Pair
+	HasSize bool
+	Size    int64
+}
+
+// parsePairStorageQuerySignHTTP will parse Pair slice into *pairStorageQuerySignHTTP
+func (s *Storage) parsePairStorageQuerySignHTTP(opts []Pair) (pairStorageQuerySignHTTP, error) {
+	result := pairStorageQuerySignHTTP{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		case "object_mode":
-			if result.HasObjectMode {
+		case "size":
+			if result.HasSize {
 				continue
 			}
-			result.HasObjectMode = true
-			result.ObjectMode = v.Value.(ObjectMode)
+			result.HasSize = true
+			result.Size = v.Value.(int64)
 			continue
 		default:
-			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageQuerySignHTTP{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -309,30 +3587,150 @@ func (s *Storage) parsePairStorageDelete(opts []Pair) (pairStorageDelete, error)
 	return result, nil
 }
 
-// pairStorageList is the parsed struct
-type pairStorageList struct {
-	pairs       []Pair
-	HasListMode bool
-	ListMode    ListMode
+// pairStorageRead is the parsed struct
+type pairStorageRead struct {
+	pairs                []Pair
+	HasCallOptions       bool
+	CallOptions          CallOptions
+	HasChunkSize         bool
+	ChunkSize            int64
+	HasClientRequestID   bool
+	ClientRequestID      string
+	HasConcurrency       bool
+	Concurrency          int
+	HasIfMatch           bool
+	IfMatch              string
+	HasIfModifiedSince   bool
+	IfModifiedSince      time.Time
+	HasIfNoneMatch       bool
+	IfNoneMatch          string
+	HasIfUnmodifiedSince bool
+	IfUnmodifiedSince    time.Time
+	HasIoCallback        bool
+	IoCallback           func([]byte)
+	HasOffset            bool
+	Offset               int64
+	HasReadRetries       bool
+	ReadRetries          int
+	HasSize              bool
+	Size                 int64
+	HasTimeout           bool
+	Timeout              time.Duration
+	HasVerifyContentMd5  bool
+	VerifyContentMd5     bool
 }
 
-// parsePairStorageList will parse Pair slice into *pairStorageList
-func (s *Storage) parsePairStorageList(opts []Pair) (pairStorageList, error) {
-	result := pairStorageList{
+// parsePairStorageRead will parse Pair slice into *pairStorageRead
+func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
+	result := pairStorageRead{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		case "list_mode":
-			if result.HasListMode {
+		case "call_options":
+			if result.HasCallOptions {
 				continue
 			}
-			result.HasListMode = true
-			result.ListMode = v.Value.(ListMode)
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "chunk_size":
+			if result.HasChunkSize {
+				continue
+			}
+			result.HasChunkSize = true
+			result.ChunkSize = v.Value.(int64)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+			continue
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+			continue
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+			continue
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
+			continue
+		case "io_callback":
+			if result.HasIoCallback {
+				continue
+			}
+			result.HasIoCallback = true
+			result.IoCallback = v.Value.(func([]byte))
+			continue
+		case "offset":
+			if result.HasOffset {
+				continue
+			}
+			result.HasOffset = true
+			result.Offset = v.Value.(int64)
+			continue
+		case "read_retries":
+			if result.HasReadRetries {
+				continue
+			}
+			result.HasReadRetries = true
+			result.ReadRetries = v.Value.(int)
+			continue
+		case "size":
+			if result.HasSize {
+				continue
+			}
+			result.HasSize = true
+			result.Size = v.Value.(int64)
+			continue
+		case "timeout":
+			if result.HasTimeout {
+				continue
+			}
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
+			continue
+		case "verify_content_md5":
+			if result.HasVerifyContentMd5 {
+				continue
+			}
+			result.HasVerifyContentMd5 = true
+			result.VerifyContentMd5 = v.Value.(bool)
 			continue
 		default:
-			return pairStorageList{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -341,21 +3739,69 @@ func (s *Storage) parsePairStorageList(opts []Pair) (pairStorageList, error) {
 	return result, nil
 }
 
-// pairStorageMetadata is the parsed struct
-type pairStorageMetadata struct {
-	pairs []Pair
+// pairStorageStat is the parsed struct
+type pairStorageStat struct {
+	pairs                    []Pair
+	HasCallOptions           bool
+	CallOptions              CallOptions
+	HasClientRequestID       bool
+	ClientRequestID          string
+	HasObjectMode            bool
+	ObjectMode               ObjectMode
+	HasResolveFilePermission bool
+	ResolveFilePermission    bool
+	HasTimeout               bool
+	Timeout                  time.Duration
 }
 
-// parsePairStorageMetadata will parse Pair slice into *pairStorageMetadata
-func (s *Storage) parsePairStorageMetadata(opts []Pair) (pairStorageMetadata, error) {
-	result := pairStorageMetadata{
+// parsePairStorageStat will parse Pair slice into *pairStorageStat
+func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
+	result := pairStorageStat{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "object_mode":
+			if result.HasObjectMode {
+				continue
+			}
+			result.HasObjectMode = true
+			result.ObjectMode = v.Value.(ObjectMode)
+			continue
+		case "resolve_file_permission":
+			if result.HasResolveFilePermission {
+				continue
+			}
+			result.HasResolveFilePermission = true
+			result.ResolveFilePermission = v.Value.(bool)
+			continue
+		case "timeout":
+			if result.HasTimeout {
+				continue
+			}
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
+			continue
 		default:
-			return pairStorageMetadata{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -364,25 +3810,251 @@ func (s *Storage) parsePairStorageMetadata(opts []Pair) (pairStorageMetadata, er
 	return result, nil
 }
 
-// pairStorageRead is the parsed struct
-type pairStorageRead struct {
-	pairs         []Pair
-	HasIoCallback bool
-	IoCallback    func([]byte)
-	HasOffset     bool
-	Offset        int64
-	HasSize       bool
-	Size          int64
+// pairStorageWrite is the parsed struct
+type pairStorageWrite struct {
+	pairs                 []Pair
+	HasAtomicWrite        bool
+	AtomicWrite           bool
+	HasCacheControl       bool
+	CacheControl          string
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasCheckQuota         bool
+	CheckQuota            bool
+	HasCheckpointStore    bool
+	CheckpointStore       CheckpointStore
+	HasChunkSize          bool
+	ChunkSize             int64
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasComputeContentMd5  bool
+	ComputeContentMd5     bool
+	HasConcurrency        bool
+	Concurrency           int
+	HasContentDisposition bool
+	ContentDisposition    string
+	HasContentEncoding    bool
+	ContentEncoding       string
+	HasContentLanguage    bool
+	ContentLanguage       string
+	HasContentMd5         bool
+	ContentMd5            string
+	HasContentType        bool
+	ContentType           string
+	HasCreateOnly         bool
+	CreateOnly            bool
+	HasFileAttributes     bool
+	FileAttributes        string
+	HasFileCreationTime   bool
+	FileCreationTime      time.Time
+	HasFileLastWriteTime  bool
+	FileLastWriteTime     time.Time
+	HasFilePermission     bool
+	FilePermission        string
+	HasFilePermissionKey  bool
+	FilePermissionKey     string
+	HasIfMatch            bool
+	IfMatch               string
+	HasIfModifiedSince    bool
+	IfModifiedSince       time.Time
+	HasIfNoneMatch        bool
+	IfNoneMatch           string
+	HasIfUnmodifiedSince  bool
+	IfUnmodifiedSince     time.Time
+	HasIoCallback         bool
+	IoCallback            func([]byte)
+	HasLeaseID            bool
+	LeaseID               string
+	HasOffset             bool
+	Offset                int64
+	HasPreserveFileInfo   bool
+	PreserveFileInfo      bool
+	HasTimeout            bool
+	Timeout               time.Duration
+	HasTransactionalCRC64 bool
+	TransactionalCRC64    bool
+	HasUserMetadata       bool
+	UserMetadata          map[string]string
+	HasVerifyTransfer     bool
+	VerifyTransfer        bool
 }
 
-// parsePairStorageRead will parse Pair slice into *pairStorageRead
-func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
-	result := pairStorageRead{
+// parsePairStorageWrite will parse Pair slice into *pairStorageWrite
+func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
+	result := pairStorageWrite{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
+		case "atomic_write":
+			if result.HasAtomicWrite {
+				continue
+			}
+			result.HasAtomicWrite = true
+			result.AtomicWrite = v.Value.(bool)
+			continue
+		case "cache_control":
+			if result.HasCacheControl {
+				continue
+			}
+			result.HasCacheControl = true
+			result.CacheControl = v.Value.(string)
+			continue
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "check_quota":
+			if result.HasCheckQuota {
+				continue
+			}
+			result.HasCheckQuota = true
+			result.CheckQuota = v.Value.(bool)
+			continue
+		case "checkpoint_store":
+			if result.HasCheckpointStore {
+				continue
+			}
+			result.HasCheckpointStore = true
+			result.CheckpointStore = v.Value.(CheckpointStore)
+			continue
+		case "chunk_size":
+			if result.HasChunkSize {
+				continue
+			}
+			result.HasChunkSize = true
+			result.ChunkSize = v.Value.(int64)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "compute_content_md5":
+			if result.HasComputeContentMd5 {
+				continue
+			}
+			result.HasComputeContentMd5 = true
+			result.ComputeContentMd5 = v.Value.(bool)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "content_disposition":
+			if result.HasContentDisposition {
+				continue
+			}
+			result.HasContentDisposition = true
+			result.ContentDisposition = v.Value.(string)
+			continue
+		case "content_encoding":
+			if result.HasContentEncoding {
+				continue
+			}
+			result.HasContentEncoding = true
+			result.ContentEncoding = v.Value.(string)
+			continue
+		case "content_language":
+			if result.HasContentLanguage {
+				continue
+			}
+			result.HasContentLanguage = true
+			result.ContentLanguage = v.Value.(string)
+			continue
+		case "content_md5":
+			if result.HasContentMd5 {
+				continue
+			}
+			result.HasContentMd5 = true
+			result.ContentMd5 = v.Value.(string)
+			continue
+		case "content_type":
+			if result.HasContentType {
+				continue
+			}
+			result.HasContentType = true
+			result.ContentType = v.Value.(string)
+			continue
+		case "create_only":
+			if result.HasCreateOnly {
+				continue
+			}
+			result.HasCreateOnly = true
+			result.CreateOnly = v.Value.(bool)
+			continue
+		case "file_attributes":
+			if result.HasFileAttributes {
+				continue
+			}
+			result.HasFileAttributes = true
+			result.FileAttributes = v.Value.(string)
+			continue
+		case "file_creation_time":
+			if result.HasFileCreationTime {
+				continue
+			}
+			result.HasFileCreationTime = true
+			result.FileCreationTime = v.Value.(time.Time)
+			continue
+		case "file_last_write_time":
+			if result.HasFileLastWriteTime {
+				continue
+			}
+			result.HasFileLastWriteTime = true
+			result.FileLastWriteTime = v.Value.(time.Time)
+			continue
+		case "file_permission":
+			if result.HasFilePermission {
+				continue
+			}
+			result.HasFilePermission = true
+			result.FilePermission = v.Value.(string)
+			continue
+		case "file_permission_key":
+			if result.HasFilePermissionKey {
+				continue
+			}
+			result.HasFilePermissionKey = true
+			result.FilePermissionKey = v.Value.(string)
+			continue
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+			continue
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+			continue
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+			continue
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
+			continue
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -390,6 +4062,13 @@ func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
 			result.HasIoCallback = true
 			result.IoCallback = v.Value.(func([]byte))
 			continue
+		case "lease_id":
+			if result.HasLeaseID {
+				continue
+			}
+			result.HasLeaseID = true
+			result.LeaseID = v.Value.(string)
+			continue
 		case "offset":
 			if result.HasOffset {
 				continue
@@ -397,15 +4076,46 @@ func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
 			result.HasOffset = true
 			result.Offset = v.Value.(int64)
 			continue
-		case "size":
-			if result.HasSize {
+		case "preserve_file_info":
+			if result.HasPreserveFileInfo {
 				continue
 			}
-			result.HasSize = true
-			result.Size = v.Value.(int64)
+			result.HasPreserveFileInfo = true
+			result.PreserveFileInfo = v.Value.(bool)
+			continue
+		case "timeout":
+			if result.HasTimeout {
+				continue
+			}
+			result.HasTimeout = true
+			result.Timeout = v.Value.(time.Duration)
+			continue
+		case "transactional_crc64":
+			if result.HasTransactionalCRC64 {
+				continue
+			}
+			result.HasTransactionalCRC64 = true
+			result.TransactionalCRC64 = v.Value.(bool)
+			continue
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
+			continue
+		case "verify_transfer":
+			if result.HasVerifyTransfer {
+				continue
+			}
+			result.HasVerifyTransfer = true
+			result.VerifyTransfer = v.Value.(bool)
 			continue
 		default:
-			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -414,30 +4124,87 @@ func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
 	return result, nil
 }
 
-// pairStorageStat is the parsed struct
-type pairStorageStat struct {
-	pairs         []Pair
-	HasObjectMode bool
-	ObjectMode    ObjectMode
+// pairStorageWriteAppend is the parsed struct
+type pairStorageWriteAppend struct {
+	pairs                 []Pair
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasChunkSize          bool
+	ChunkSize             int64
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasConcurrency        bool
+	Concurrency           int
+	HasIoCallback         bool
+	IoCallback            func([]byte)
+	HasLeaseID            bool
+	LeaseID               string
+	HasTransactionalCRC64 bool
+	TransactionalCRC64    bool
 }
 
-// parsePairStorageStat will parse Pair slice into *pairStorageStat
-func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
-	result := pairStorageStat{
+// parsePairStorageWriteAppend will parse Pair slice into *pairStorageWriteAppend
+func (s *Storage) parsePairStorageWriteAppend(opts []Pair) (pairStorageWriteAppend, error) {
+	result := pairStorageWriteAppend{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		case "object_mode":
-			if result.HasObjectMode {
+		case "call_options":
+			if result.HasCallOptions {
+				continue
+			}
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
+			continue
+		case "chunk_size":
+			if result.HasChunkSize {
+				continue
+			}
+			result.HasChunkSize = true
+			result.ChunkSize = v.Value.(int64)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
+			continue
+		case "io_callback":
+			if result.HasIoCallback {
 				continue
 			}
-			result.HasObjectMode = true
-			result.ObjectMode = v.Value.(ObjectMode)
+			result.HasIoCallback = true
+			result.IoCallback = v.Value.(func([]byte))
+			continue
+		case "lease_id":
+			if result.HasLeaseID {
+				continue
+			}
+			result.HasLeaseID = true
+			result.LeaseID = v.Value.(string)
+			continue
+		case "transactional_crc64":
+			if result.HasTransactionalCRC64 {
+				continue
+			}
+			result.HasTransactionalCRC64 = true
+			result.TransactionalCRC64 = v.Value.(bool)
 			continue
 		default:
-			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageWriteAppend{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -446,38 +4213,58 @@ func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
 	return result, nil
 }
 
-// pairStorageWrite is the parsed struct
-type pairStorageWrite struct {
-	pairs          []Pair
-	HasContentMd5  bool
-	ContentMd5     string
-	HasContentType bool
-	ContentType    string
-	HasIoCallback  bool
-	IoCallback     func([]byte)
+// pairStorageWriteMultipart is the parsed struct
+type pairStorageWriteMultipart struct {
+	pairs                 []Pair
+	HasCallOptions        bool
+	CallOptions           CallOptions
+	HasChunkSize          bool
+	ChunkSize             int64
+	HasClientRequestID    bool
+	ClientRequestID       string
+	HasConcurrency        bool
+	Concurrency           int
+	HasIoCallback         bool
+	IoCallback            func([]byte)
+	HasTransactionalCRC64 bool
+	TransactionalCRC64    bool
 }
 
-// parsePairStorageWrite will parse Pair slice into *pairStorageWrite
-func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
-	result := pairStorageWrite{
+// parsePairStorageWriteMultipart will parse Pair slice into *pairStorageWriteMultipart
+func (s *Storage) parsePairStorageWriteMultipart(opts []Pair) (pairStorageWriteMultipart, error) {
+	result := pairStorageWriteMultipart{
 		pairs: opts,
 	}
 
 	for _, v := range opts {
 		switch v.Key {
-		case "content_md5":
-			if result.HasContentMd5 {
+		case "call_options":
+			if result.HasCallOptions {
 				continue
 			}
-			result.HasContentMd5 = true
-			result.ContentMd5 = v.Value.(string)
+			result.HasCallOptions = true
+			result.CallOptions = v.Value.(CallOptions)
 			continue
-		case "content_type":
-			if result.HasContentType {
+		case "chunk_size":
+			if result.HasChunkSize {
 				continue
 			}
-			result.HasContentType = true
-			result.ContentType = v.Value.(string)
+			result.HasChunkSize = true
+			result.ChunkSize = v.Value.(int64)
+			continue
+		case "client_request_id":
+			if result.HasClientRequestID {
+				continue
+			}
+			result.HasClientRequestID = true
+			result.ClientRequestID = v.Value.(string)
+			continue
+		case "concurrency":
+			if result.HasConcurrency {
+				continue
+			}
+			result.HasConcurrency = true
+			result.Concurrency = v.Value.(int)
 			continue
 		case "io_callback":
 			if result.HasIoCallback {
@@ -486,8 +4273,18 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 			result.HasIoCallback = true
 			result.IoCallback = v.Value.(func([]byte))
 			continue
+		case "transactional_crc64":
+			if result.HasTransactionalCRC64 {
+				continue
+			}
+			result.HasTransactionalCRC64 = true
+			result.TransactionalCRC64 = v.Value.(bool)
+			continue
 		default:
-			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
+			if s.features.LoosePair {
+				continue
+			}
+			return pairStorageWriteMultipart{}, services.PairUnsupportedError{Pair: v}
 		}
 	}
 
@@ -496,6 +4293,103 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 	return result, nil
 }
 
+// CommitAppend will commit and finish an append process.
+//
+// This function will create a context by default.
+func (s *Storage) CommitAppend(o *Object, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.CommitAppendWithContext(ctx, o, pairs...)
+}
+
+// CommitAppendWithContext will commit and finish an append process.
+func (s *Storage) CommitAppendWithContext(ctx context.Context, o *Object, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("commit_append", err, o.Path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.CommitAppend...)
+	var opt pairStorageCommitAppend
+
+	opt, err = s.parsePairStorageCommitAppend(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.commitAppend(ctx, o, opt)
+}
+
+// CompleteMultipart will complete a multipart upload and construct an Object.
+//
+// This function will create a context by default.
+func (s *Storage) CompleteMultipart(o *Object, parts []*Part, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.CompleteMultipartWithContext(ctx, o, parts, pairs...)
+}
+
+// CompleteMultipartWithContext will complete a multipart upload and construct an Object.
+func (s *Storage) CompleteMultipartWithContext(ctx context.Context, o *Object, parts []*Part, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("complete_multipart", err, o.Path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.CompleteMultipart...)
+	var opt pairStorageCompleteMultipart
+
+	opt, err = s.parsePairStorageCompleteMultipart(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.completeMultipart(ctx, o, parts, opt)
+}
+
+// Copy will copy an Object or multiple object in the service.
+//
+// ## Behavior
+//
+// - Copy only copy one and only one object.
+//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
+//   - User NEED to implement copy a non-empty directory and copy recursively by themself.
+//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
+// - Copy SHOULD NOT return an error as dst object exists.
+//   - Service that has native support for `overwrite` doesn't NEED to check the dst object exists or not.
+//   - Service that doesn't have native support for `overwrite` SHOULD check and delete the dst object if exists.
+// - A successful copy opration should be complete, which means the dst object's content and metadata should be the same as src object.
+//
+// This function will create a context by default.
+func (s *Storage) Copy(src string, dst string, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.CopyWithContext(ctx, src, dst, pairs...)
+}
+
+// CopyWithContext will copy an Object or multiple object in the service.
+//
+// ## Behavior
+//
+// - Copy only copy one and only one object.
+//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
+//   - User NEED to implement copy a non-empty directory and copy recursively by themself.
+//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
+// - Copy SHOULD NOT return an error as dst object exists.
+//   - Service that has native support for `overwrite` doesn't NEED to check the dst object exists or not.
+//   - Service that doesn't have native support for `overwrite` SHOULD check and delete the dst object if exists.
+// - A successful copy opration should be complete, which means the dst object's content and metadata should be the same as src object.
+func (s *Storage) CopyWithContext(ctx context.Context, src string, dst string, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("copy", err, src, dst)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Copy...)
+	var opt pairStorageCopy
+
+	opt, err = s.parsePairStorageCopy(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.copy(ctx, src, dst, opt)
+}
+
 // Create will create a new object without any api call.
 //
 // ## Behavior
@@ -514,6 +4408,43 @@ func (s *Storage) Create(path string, pairs ...Pair) (o *Object) {
 	return s.create(path, opt)
 }
 
+// CreateAppend will create an append object.
+//
+// ## Behavior
+//
+// - CreateAppend SHOULD create an appendable object with position 0 and size 0.
+// - CreateAppend SHOULD NOT return an error as the object exist.
+//   - Service SHOULD check and delete the object if exists.
+//
+// This function will create a context by default.
+func (s *Storage) CreateAppend(path string, pairs ...Pair) (o *Object, err error) {
+	ctx := context.Background()
+	return s.CreateAppendWithContext(ctx, path, pairs...)
+}
+
+// CreateAppendWithContext will create an append object.
+//
+// ## Behavior
+//
+// - CreateAppend SHOULD create an appendable object with position 0 and size 0.
+// - CreateAppend SHOULD NOT return an error as the object exist.
+//   - Service SHOULD check and delete the object if exists.
+func (s *Storage) CreateAppendWithContext(ctx context.Context, path string, pairs ...Pair) (o *Object, err error) {
+	defer func() {
+		err = s.formatError("create_append", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.CreateAppend...)
+	var opt pairStorageCreateAppend
+
+	opt, err = s.parsePairStorageCreateAppend(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.createAppend(ctx, path, opt)
+}
+
 // CreateDir will create a new dir object.
 //
 // This function will create a context by default.
@@ -539,6 +4470,86 @@ func (s *Storage) CreateDirWithContext(ctx context.Context, path string, pairs .
 	return s.createDir(ctx, path, opt)
 }
 
+// CreateLink Will create a link object.
+//
+// ## Behavior
+//
+// - `path` and `target` COULD be relative or absolute path.
+// - If `target` not exists, CreateLink will still create a link object to path.
+// - If `path` exists:
+//   - If `path` is a symlink object, CreateLink will remove the symlink object and create a new link object to path.
+//   - If `path` is a not symlink object, CreateLink will return an ErrObjectModeInvalid error when the service does not support overwrite.
+// - A link object COULD be returned in `Stat` or `List`.
+// - CreateLink COULD implement virtual_link feature when service without native support.
+//   - Users SHOULD enable this feature by themselves.
+//
+// This function will create a context by default.
+func (s *Storage) CreateLink(path string, target string, pairs ...Pair) (o *Object, err error) {
+	ctx := context.Background()
+	return s.CreateLinkWithContext(ctx, path, target, pairs...)
+}
+
+// CreateLinkWithContext Will create a link object.
+//
+// ## Behavior
+//
+// - `path` and `target` COULD be relative or absolute path.
+// - If `target` not exists, CreateLink will still create a link object to path.
+// - If `path` exists:
+//   - If `path` is a symlink object, CreateLink will remove the symlink object and create a new link object to path.
+//   - If `path` is a not symlink object, CreateLink will return an ErrObjectModeInvalid error when the service does not support overwrite.
+// - A link object COULD be returned in `Stat` or `List`.
+// - CreateLink COULD implement virtual_link feature when service without native support.
+//   - Users SHOULD enable this feature by themselves.
+func (s *Storage) CreateLinkWithContext(ctx context.Context, path string, target string, pairs ...Pair) (o *Object, err error) {
+	defer func() {
+		err = s.formatError("create_link", err, path, target)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.CreateLink...)
+	var opt pairStorageCreateLink
+
+	opt, err = s.parsePairStorageCreateLink(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.createLink(ctx, path, target, opt)
+}
+
+// CreateMultipart will create a new multipart.
+//
+// ## Behavior
+//
+// - CreateMultipart SHOULD NOT return an error as the object exists.
+//
+// This function will create a context by default.
+func (s *Storage) CreateMultipart(path string, pairs ...Pair) (o *Object, err error) {
+	ctx := context.Background()
+	return s.CreateMultipartWithContext(ctx, path, pairs...)
+}
+
+// CreateMultipartWithContext will create a new multipart.
+//
+// ## Behavior
+//
+// - CreateMultipart SHOULD NOT return an error as the object exists.
+func (s *Storage) CreateMultipartWithContext(ctx context.Context, path string, pairs ...Pair) (o *Object, err error) {
+	defer func() {
+		err = s.formatError("create_multipart", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.CreateMultipart...)
+	var opt pairStorageCreateMultipart
+
+	opt, err = s.parsePairStorageCreateMultipart(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.createMultipart(ctx, path, opt)
+}
+
 // Delete will delete an object from service.
 //
 // ## Behavior
@@ -584,6 +4595,41 @@ func (s *Storage) DeleteWithContext(ctx context.Context, path string, pairs ...P
 	return s.delete(ctx, path, opt)
 }
 
+// Fetch will fetch from a given url to path.
+//
+// ## Behavior
+//
+// - Fetch SHOULD NOT return an error as the object exists.
+// - A successful fetch operation should be complete, which means the object's content and metadata should be the same as requiring from the url.
+//
+// This function will create a context by default.
+func (s *Storage) Fetch(path string, url string, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.FetchWithContext(ctx, path, url, pairs...)
+}
+
+// FetchWithContext will fetch from a given url to path.
+//
+// ## Behavior
+//
+// - Fetch SHOULD NOT return an error as the object exists.
+// - A successful fetch operation should be complete, which means the object's content and metadata should be the same as requiring from the url.
+func (s *Storage) FetchWithContext(ctx context.Context, path string, url string, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("fetch", err, path, url)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Fetch...)
+	var opt pairStorageFetch
+
+	opt, err = s.parsePairStorageFetch(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.fetch(ctx, path, url, opt)
+}
+
 // List will return list a specific path.
 //
 // ## Behavior
@@ -621,6 +4667,31 @@ func (s *Storage) ListWithContext(ctx context.Context, path string, pairs ...Pai
 	return s.list(ctx, path, opt)
 }
 
+// ListMultipart will list parts belong to this multipart.
+//
+// This function will create a context by default.
+func (s *Storage) ListMultipart(o *Object, pairs ...Pair) (pi *PartIterator, err error) {
+	ctx := context.Background()
+	return s.ListMultipartWithContext(ctx, o, pairs...)
+}
+
+// ListMultipartWithContext will list parts belong to this multipart.
+func (s *Storage) ListMultipartWithContext(ctx context.Context, o *Object, pairs ...Pair) (pi *PartIterator, err error) {
+	defer func() {
+		err = s.formatError("list_multipart", err, o.Path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.ListMultipart...)
+	var opt pairStorageListMultipart
+
+	opt, err = s.parsePairStorageListMultipart(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.listMultipart(ctx, o, opt)
+}
+
 // Metadata will return current storager metadata.
 //
 // This function will create a context by default.
@@ -634,6 +4705,78 @@ func (s *Storage) Metadata(pairs ...Pair) (meta *StorageMeta) {
 	return s.metadata(opt)
 }
 
+// Move will move an object in the service.
+//
+// ## Behavior
+//
+// - Move only move one and only one object.
+//   - Service DON'T NEED to support move a non-empty directory.
+//   - User NEED to implement move a non-empty directory by themself.
+//   - Service DON'T NEED to support move a non-empty directory.
+// - Move SHOULD NOT return an error as dst object exists.
+//   - Service that has native support for `overwrite` doesn't NEED to check the dst object exists or not.
+//   - Service that doesn't have native support for `overwrite` SHOULD check and delete the dst object if exists.
+// - A successful move operation SHOULD be complete, which means the dst object's content and metadata should be the same as src object.
+//
+// This function will create a context by default.
+func (s *Storage) Move(src string, dst string, pairs ...Pair) (err error) {
+	ctx := context.Background()
+	return s.MoveWithContext(ctx, src, dst, pairs...)
+}
+
+// MoveWithContext will move an object in the service.
+//
+// ## Behavior
+//
+// - Move only move one and only one object.
+//   - Service DON'T NEED to support move a non-empty directory.
+//   - User NEED to implement move a non-empty directory by themself.
+//   - Service DON'T NEED to support move a non-empty directory.
+// - Move SHOULD NOT return an error as dst object exists.
+//   - Service that has native support for `overwrite` doesn't NEED to check the dst object exists or not.
+//   - Service that doesn't have native support for `overwrite` SHOULD check and delete the dst object if exists.
+// - A successful move operation SHOULD be complete, which means the dst object's content and metadata should be the same as src object.
+func (s *Storage) MoveWithContext(ctx context.Context, src string, dst string, pairs ...Pair) (err error) {
+	defer func() {
+		err = s.formatError("move", err, src, dst)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Move...)
+	var opt pairStorageMove
+
+	opt, err = s.parsePairStorageMove(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.move(ctx, src, dst, opt)
+}
+
+// QuerySignHTTP will return `*http.Request` with query string parameters containing signature in `URL` to represent the client's request.
+//
+// This function will create a context by default.
+func (s *Storage) QuerySignHTTP(op string, path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	ctx := context.Background()
+	return s.QuerySignHTTPWithContext(ctx, op, path, expire, pairs...)
+}
+
+// QuerySignHTTPWithContext will return `*http.Request` with query string parameters containing signature in `URL` to represent the client's request.
+func (s *Storage) QuerySignHTTPWithContext(ctx context.Context, op string, path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	defer func() {
+		err = s.formatError("query_sign_http", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTP...)
+	var opt pairStorageQuerySignHTTP
+
+	opt, err = s.parsePairStorageQuerySignHTTP(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.querySignHTTP(ctx, op, path, expire, opt)
+}
+
 // Read will read the file's data.
 //
 // This function will create a context by default.
@@ -735,7 +4878,58 @@ func (s *Storage) WriteWithContext(ctx context.Context, path string, r io.Reader
 	return s.write(ctx, path, r, size, opt)
 }
 
+// WriteAppend will append content to an append object.
+//
+// This function will create a context by default.
+func (s *Storage) WriteAppend(o *Object, r io.Reader, size int64, pairs ...Pair) (n int64, err error) {
+	ctx := context.Background()
+	return s.WriteAppendWithContext(ctx, o, r, size, pairs...)
+}
+
+// WriteAppendWithContext will append content to an append object.
+func (s *Storage) WriteAppendWithContext(ctx context.Context, o *Object, r io.Reader, size int64, pairs ...Pair) (n int64, err error) {
+	defer func() {
+		err = s.formatError("write_append", err, o.Path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.WriteAppend...)
+	var opt pairStorageWriteAppend
+
+	opt, err = s.parsePairStorageWriteAppend(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.writeAppend(ctx, o, r, size, opt)
+}
+
+// WriteMultipart will write content to a multipart.
+//
+// This function will create a context by default.
+func (s *Storage) WriteMultipart(o *Object, r io.Reader, size int64, index int, pairs ...Pair) (n int64, part *Part, err error) {
+	ctx := context.Background()
+	return s.WriteMultipartWithContext(ctx, o, r, size, index, pairs...)
+}
+
+// WriteMultipartWithContext will write content to a multipart.
+func (s *Storage) WriteMultipartWithContext(ctx context.Context, o *Object, r io.Reader, size int64, index int, pairs ...Pair) (n int64, part *Part, err error) {
+	defer func() {
+		err = s.formatError("write_multipart", err, o.Path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.WriteMultipart...)
+	var opt pairStorageWriteMultipart
+
+	opt, err = s.parsePairStorageWriteMultipart(pairs)
+	if err != nil {
+		return
+	}
+
+	return s.writeMultipart(ctx, o, r, size, index, opt)
+}
+
 func init() {
+	services.RegisterServicer(Type, NewServicer)
 	services.RegisterStorager(Type, NewStorager)
 	services.RegisterSchema(Type, pairMap)
 }
@@ -1,10 +1,22 @@
 module github.com/beyondstorage/go-service-azfile
 
-go 1.15
+go 1.23.0
 
 require (
-	github.com/Azure/azure-storage-file-go v0.8.0
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.0
+	github.com/Azure/azure-sdk-for-go/sdk/storage/azfile v1.5.1
 	github.com/beyondstorage/go-endpoint v1.1.0
 	github.com/beyondstorage/go-storage/v4 v4.6.0
+	go.opentelemetry.io/otel v1.21.0
+	go.opentelemetry.io/otel/trace v1.21.0
+)
+
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/internal v1.11.1 // indirect
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
+	go.opentelemetry.io/otel/metric v1.21.0 // indirect
+	golang.org/x/net v0.38.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
 )
@@ -0,0 +1,164 @@
+package azfile
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// HandleIDAll could be used in ForceCloseHandles to close all handles.
+const HandleIDAll = "*"
+
+// Handle is an open SMB handle on a file or directory.
+type Handle struct {
+	ID        string
+	Path      string
+	FileID    string
+	ParentID  string
+	SessionID string
+	ClientIP  string
+
+	OpenTime          time.Time
+	LastReconnectTime time.Time
+}
+
+// ListHandles will list all open handles on the file, or the directory and its children
+// if the mode is dir.
+//
+// This function will create a context by default.
+func (s *Storage) ListHandles(path string, mode types.ObjectMode) (handles []Handle, err error) {
+	return s.ListHandlesWithContext(context.Background(), path, mode)
+}
+
+// ListHandlesWithContext will list all open handles on the file, or the directory and its children
+// if the mode is dir.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/list-handles
+func (s *Storage) ListHandlesWithContext(ctx context.Context, path string, mode types.ObjectMode) (handles []Handle, err error) {
+	defer func() {
+		err = s.formatError("list_handles", err, path)
+	}()
+
+	var marker *string
+	for {
+		var items []*file.Handle
+		if mode.IsDir() {
+			output, err := s.dirClient(path).ListHandles(ctx, &directory.ListHandlesOptions{
+				Marker:    marker,
+				Recursive: to.Ptr(true),
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, marker = output.Handles, output.NextMarker
+		} else {
+			output, err := s.fileClient(path).ListHandles(ctx, &file.ListHandlesOptions{
+				Marker: marker,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, marker = output.Handles, output.NextMarker
+		}
+
+		for _, v := range items {
+			handles = append(handles, formatHandle(v))
+		}
+
+		if marker == nil || *marker == "" {
+			return handles, nil
+		}
+	}
+}
+
+// ForceCloseHandles will close the handle on the file, or on the directory and its children
+// if the mode is dir, and return the number of closed handles.
+//
+// Use HandleIDAll to close all handles.
+//
+// This function will create a context by default.
+func (s *Storage) ForceCloseHandles(path string, mode types.ObjectMode, handleID string) (closed int, err error) {
+	return s.ForceCloseHandlesWithContext(context.Background(), path, mode, handleID)
+}
+
+// ForceCloseHandlesWithContext will close the handle on the file, or on the directory and its children
+// if the mode is dir, and return the number of closed handles.
+//
+// Use HandleIDAll to close all handles.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/force-close-handles
+func (s *Storage) ForceCloseHandlesWithContext(ctx context.Context, path string, mode types.ObjectMode, handleID string) (closed int, err error) {
+	defer func() {
+		err = s.formatError("force_close_handles", err, path)
+	}()
+
+	if s.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	// Service could close part of handles in one request, and return a marker to continue.
+	var marker *string
+	for {
+		var n *int32
+		if mode.IsDir() {
+			output, err := s.dirClient(path).ForceCloseHandles(ctx, handleID, &directory.ForceCloseHandlesOptions{
+				Marker:    marker,
+				Recursive: to.Ptr(true),
+			})
+			if err != nil {
+				return closed, err
+			}
+			n, marker = output.NumberOfHandlesClosed, output.Marker
+		} else {
+			output, err := s.fileClient(path).ForceCloseHandles(ctx, handleID, &file.ForceCloseHandlesOptions{
+				Marker: marker,
+			})
+			if err != nil {
+				return closed, err
+			}
+			n, marker = output.NumberOfHandlesClosed, output.Marker
+		}
+
+		if n != nil {
+			closed += int(*n)
+		}
+
+		if marker == nil || *marker == "" {
+			return closed, nil
+		}
+	}
+}
+
+func formatHandle(v *file.Handle) Handle {
+	var h Handle
+	if v.ID != nil {
+		h.ID = *v.ID
+	}
+	if v.Path != nil {
+		h.Path = *v.Path
+	}
+	if v.FileID != nil {
+		h.FileID = *v.FileID
+	}
+	if v.ParentID != nil {
+		h.ParentID = *v.ParentID
+	}
+	if v.SessionID != nil {
+		h.SessionID = *v.SessionID
+	}
+	if v.ClientIP != nil {
+		h.ClientIP = *v.ClientIP
+	}
+	if v.OpenTime != nil {
+		h.OpenTime = *v.OpenTime
+	}
+	if v.LastReconnectTime != nil {
+		h.LastReconnectTime = *v.LastReconnectTime
+	}
+	return h
+}
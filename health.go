@@ -0,0 +1,48 @@
+package azfile
+
+import (
+	"context"
+	"time"
+)
+
+// CheckResult is the result of the health check.
+type CheckResult struct {
+	// Healthy is true if the share is accessible with our credential.
+	Healthy bool
+	// Latency is the round trip time of the check request, including retries.
+	Latency time.Duration
+	// RequestID is the x-ms-request-id returned by service, it's empty if no
+	// response is received.
+	RequestID string
+	// Err is the reason why the check failed.
+	Err error
+}
+
+// Check will check whether the share is accessible, which is suitable for readiness probes.
+//
+// This function will create a context by default.
+func (s *Storage) Check() CheckResult {
+	return s.CheckWithContext(context.Background())
+}
+
+// CheckWithContext will check whether the share is accessible by getting the
+// properties of the share, which is cheaper than listing and requires authentication.
+//
+// Use a ctx with deadline to bound the probe, otherwise the retry options apply.
+func (s *Storage) CheckWithContext(ctx context.Context) CheckResult {
+	start := time.Now()
+	output, err := s.share.GetProperties(ctx, nil)
+
+	result := CheckResult{Latency: time.Since(start)}
+	if err != nil {
+		result.Err = s.formatError("check", err)
+		result.RequestID = GetRequestID(result.Err)
+		return result
+	}
+
+	result.Healthy = true
+	if output.RequestID != nil {
+		result.RequestID = *output.RequestID
+	}
+	return result
+}
@@ -1,16 +1,112 @@
 package azfile
 
-import "github.com/Azure/azure-storage-file-go/azfile"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type objectPageStatus struct {
 	maxResults int32
 	prefix     string
-	marker     azfile.Marker
+	marker     *string
+	// timeout will be applied to every page request.
+	timeout time.Duration
+	// clientRequestID will be set to every page request.
+	clientRequestID string
+	// callOptions will be applied to every page request.
+	hasCallOptions bool
+	callOptions    CallOptions
+	// extendedInfo will ask service to return the properties and SMB info of objects.
+	extendedInfo bool
+	// filter is nil if no filter pair is set.
+	filter *listFilter
+	// prefetch carries the result of the next page which is being fetched in background.
+	prefetch chan listResult
+
+	// dir is the directory being listed.
+	dir string
+	// dirs are the directories waiting to be listed in prefix mode.
+	dirs []string
+
+	// concurrency is the number of directories listed at the same time in prefix mode.
+	concurrency int
+	// parallel is the state of listing while concurrency is larger than 1.
+	parallel *parallelList
+}
+
+// listToken is the state of listing carried by continuation token.
+type listToken struct {
+	Dir    string   `json:"d"`
+	Prefix string   `json:"p,omitempty"`
+	Marker string   `json:"m,omitempty"`
+	Dirs   []string `json:"s,omitempty"`
 }
 
+// ContinuationToken will encode the state of listing, so that the listing could
+// be resumed from the next page by the continuation_token pair.
+//
+// The listing with concurrency could not be resumed, an empty token is returned.
 func (i *objectPageStatus) ContinuationToken() string {
-	if i.marker.NotDone() {
-		return *i.marker.Val
+	if i.concurrency > 1 {
+		return ""
+	}
+
+	t := listToken{
+		Dir:    i.dir,
+		Prefix: i.prefix,
+		Dirs:   i.dirs,
+	}
+	if i.marker != nil {
+		t.Marker = *i.marker
+	}
+
+	content, err := json.Marshal(t)
+	if err != nil {
+		panic(fmt.Errorf("marshal list token: %w", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// parseContinuationToken will restore the state of listing from token.
+func (i *objectPageStatus) parseContinuationToken(token string) error {
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("continuation token %q is invalid: %w", token, err)
+	}
+
+	var t listToken
+	err = json.Unmarshal(content, &t)
+	if err != nil {
+		return fmt.Errorf("continuation token %q is invalid: %w", token, err)
+	}
+
+	i.dir, i.prefix, i.dirs = t.Dir, t.Prefix, t.Dirs
+	i.marker = nil
+	if t.Marker != "" {
+		i.marker = &t.Marker
+	}
+	return nil
+}
+
+type partPageStatus struct {
+	partSize int64
+}
+
+func (i *partPageStatus) ContinuationToken() string {
+	return ""
+}
+
+type storagePageStatus struct {
+	maxResults int32
+	prefix     string
+	marker     *string
+}
+
+func (i *storagePageStatus) ContinuationToken() string {
+	if i.marker != nil {
+		return *i.marker
 	}
 	return ""
 }
@@ -0,0 +1,128 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/lease"
+)
+
+// AcquireLease will acquire an infinite lease on the file and return the lease id.
+//
+// proposedID is optional, a random one will be generated if it's empty.
+//
+// This function will create a context by default.
+func (s *Storage) AcquireLease(path string, proposedID string) (leaseID string, err error) {
+	return s.AcquireLeaseWithContext(context.Background(), path, proposedID)
+}
+
+// AcquireLeaseWithContext will acquire an infinite lease on the file and return the lease id.
+//
+// proposedID is optional, a random one will be generated if it's empty.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/lease-file
+func (s *Storage) AcquireLeaseWithContext(ctx context.Context, path string, proposedID string) (leaseID string, err error) {
+	defer func() {
+		err = s.formatError("acquire_lease", err, path)
+	}()
+
+	if s.readOnly {
+		return "", ErrReadOnly
+	}
+
+	var options *lease.FileClientOptions
+	if proposedID != "" {
+		options = &lease.FileClientOptions{LeaseID: &proposedID}
+	}
+
+	client, err := lease.NewFileClient(s.fileClient(path), options)
+	if err != nil {
+		return "", err
+	}
+
+	// File lease only supports infinite duration.
+	output, err := client.Acquire(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return *output.LeaseID, nil
+}
+
+// ReleaseLease will release the lease on the file.
+//
+// This function will create a context by default.
+func (s *Storage) ReleaseLease(path string, leaseID string) (err error) {
+	return s.ReleaseLeaseWithContext(context.Background(), path, leaseID)
+}
+
+// ReleaseLeaseWithContext will release the lease on the file.
+func (s *Storage) ReleaseLeaseWithContext(ctx context.Context, path string, leaseID string) (err error) {
+	defer func() {
+		err = s.formatError("release_lease", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	client, err := lease.NewFileClient(s.fileClient(path), &lease.FileClientOptions{LeaseID: &leaseID})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Release(ctx, nil)
+	return err
+}
+
+// BreakLease will break the lease on the file, the lease could be broken without its id.
+//
+// This function will create a context by default.
+func (s *Storage) BreakLease(path string) (err error) {
+	return s.BreakLeaseWithContext(context.Background(), path)
+}
+
+// BreakLeaseWithContext will break the lease on the file, the lease could be broken without its id.
+func (s *Storage) BreakLeaseWithContext(ctx context.Context, path string) (err error) {
+	defer func() {
+		err = s.formatError("break_lease", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	client, err := lease.NewFileClient(s.fileClient(path), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Break(ctx, nil)
+	return err
+}
+
+// lockFile will acquire a lease on the file, the returned unlock will release the
+// lease, or break it if the release failed, so that the file is not left locked.
+func lockFile(ctx context.Context, fc *file.Client) (lac *file.LeaseAccessConditions, unlock func(), err error) {
+	unlock = func() {}
+
+	// A random lease id will be generated by the client.
+	client, err := lease.NewFileClient(fc, nil)
+	if err != nil {
+		return nil, unlock, err
+	}
+
+	_, err = client.Acquire(ctx, nil)
+	if err != nil {
+		return nil, unlock, err
+	}
+	unlock = func() {
+		// The file could have been deleted by the operation, so the release is best effort.
+		_, err := client.Release(context.Background(), nil)
+		if err != nil && !checkError(err, fileNotFound) {
+			_, _ = client.Break(context.Background(), nil)
+		}
+	}
+
+	return &file.LeaseAccessConditions{LeaseID: client.LeaseID()}, unlock, nil
+}
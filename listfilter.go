@@ -0,0 +1,101 @@
+package azfile
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// listFilter filters the files listed on the client side, directories are not
+// filtered so that they could still be walked into.
+type listFilter struct {
+	suffix  string
+	pattern *regexp.Regexp
+
+	hasMinSize bool
+	minSize    int64
+	hasMaxSize bool
+	maxSize    int64
+
+	// modifiedAfter and modifiedBefore are not applied if zero.
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+}
+
+// parseListFilter will return nil if no filter pair is set.
+func parseListFilter(opt pairStorageList) (*listFilter, error) {
+	if !opt.HasListNameSuffix && !opt.HasListNameRegexp &&
+		!opt.HasListMinSize && !opt.HasListMaxSize &&
+		!opt.HasListModifiedAfter && !opt.HasListModifiedBefore {
+		return nil, nil
+	}
+
+	f := &listFilter{
+		hasMinSize: opt.HasListMinSize,
+		minSize:    opt.ListMinSize,
+		hasMaxSize: opt.HasListMaxSize,
+		maxSize:    opt.ListMaxSize,
+	}
+	if opt.HasListNameSuffix {
+		f.suffix = opt.ListNameSuffix
+	}
+	if opt.HasListNameRegexp {
+		var err error
+		f.pattern, err = regexp.Compile(opt.ListNameRegexp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.HasListModifiedAfter {
+		f.modifiedAfter = opt.ListModifiedAfter
+	}
+	if opt.HasListModifiedBefore {
+		f.modifiedBefore = opt.ListModifiedBefore
+	}
+	return f, nil
+}
+
+// timestamps will check whether the last modified time is needed, which is only
+// returned while listing with timestamps included.
+func (f *listFilter) timestamps() bool {
+	return f != nil && (!f.modifiedAfter.IsZero() || !f.modifiedBefore.IsZero())
+}
+
+// match will check the file against the filter, the name is the base name of the file.
+//
+// Files without the size or last modified time are not matched by the filters of them.
+func (f *listFilter) match(name string, o *types.Object) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.suffix != "" && !strings.HasSuffix(name, f.suffix) {
+		return false
+	}
+	if f.pattern != nil && !f.pattern.MatchString(name) {
+		return false
+	}
+
+	if f.hasMinSize || f.hasMaxSize {
+		size, ok := o.GetContentLength()
+		if !ok || f.hasMinSize && size < f.minSize || f.hasMaxSize && size > f.maxSize {
+			return false
+		}
+	}
+
+	if f.timestamps() {
+		t, ok := o.GetLastModified()
+		if !ok {
+			return false
+		}
+		if !f.modifiedAfter.IsZero() && !t.After(f.modifiedAfter) {
+			return false
+		}
+		if !f.modifiedBefore.IsZero() && !t.Before(f.modifiedBefore) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package azfile
+
+import (
+	"context"
+	pathpkg "path"
+	"sync"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// parallelList walks a tree with a fixed number of workers, every worker takes a
+// pending directory, lists all its pages and queues its sub directories.
+//
+// The files listed are queued in pages, so the order of objects is not stable. Workers
+// never block on the consumer, so they exit after the tree walked even if the iterator
+// is abandoned.
+type parallelList struct {
+	// ready is notified while pages queued, and closed after all workers exited.
+	ready  chan struct{}
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// pages are the files listed but not consumed yet.
+	pages [][]*types.Object
+	// dirs are the directories waiting to be listed.
+	dirs []*objectPageStatus
+	// active is the number of directories being listed, which could queue more.
+	active int
+	// done is set after all workers exited.
+	done bool
+	// err is the first error of workers, which is returned after pages consumed.
+	err error
+}
+
+// startParallelList will start to list the tree of input.dir with concurrency workers,
+// all workers exit once ctx is canceled or the tree is walked.
+func (s *Storage) startParallelList(ctx context.Context, input *objectPageStatus, concurrency int) *parallelList {
+	ctx, cancel := context.WithCancel(ctx)
+
+	l := &parallelList{
+		ready:  make(chan struct{}, 1),
+		cancel: cancel,
+		dirs:   []*objectPageStatus{input.shard(input.dir, input.prefix)},
+	}
+	l.cond = sync.NewCond(&l.mu)
+
+	// Workers waiting for directories should be woken up while canceled.
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		l.cond.Broadcast()
+		l.mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.parallelListWorker(ctx, cancel, l)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		stop()
+		cancel()
+
+		l.mu.Lock()
+		l.done = true
+		l.mu.Unlock()
+		close(l.ready)
+	}()
+	return l
+}
+
+func (s *Storage) parallelListWorker(ctx context.Context, cancel context.CancelFunc, l *parallelList) {
+	for {
+		l.mu.Lock()
+		for len(l.dirs) == 0 && l.active > 0 && ctx.Err() == nil {
+			l.cond.Wait()
+		}
+		// Nothing is pending or being listed, the whole tree has been walked.
+		if len(l.dirs) == 0 || ctx.Err() != nil {
+			l.cond.Broadcast()
+			l.mu.Unlock()
+			return
+		}
+		input := l.dirs[len(l.dirs)-1]
+		l.dirs = l.dirs[:len(l.dirs)-1]
+		l.active++
+		l.mu.Unlock()
+
+		err := s.parallelListDir(ctx, l, input)
+
+		l.mu.Lock()
+		l.active--
+		if err != nil && l.err == nil && ctx.Err() == nil {
+			l.err = err
+			cancel()
+		}
+		l.cond.Broadcast()
+		l.mu.Unlock()
+	}
+}
+
+// parallelListDir will list all pages of input.dir, the files are sent to l.pages
+// and the sub directories are queued.
+func (s *Storage) parallelListDir(ctx context.Context, l *parallelList, input *objectPageStatus) error {
+	for {
+		output, err := s.listFilesAndDirectories(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		if len(output.Segment.Directories) > 0 {
+			l.mu.Lock()
+			for _, v := range output.Segment.Directories {
+				l.dirs = append(l.dirs, input.shard(input.dir+*v.Name+"/", ""))
+			}
+			l.cond.Broadcast()
+			l.mu.Unlock()
+		}
+
+		var objects []*types.Object
+		for _, v := range output.Segment.Files {
+			o, err := s.formatFileObject(input.dir, v)
+			if err != nil {
+				return err
+			}
+			// The names are matched before stored by the path mapper.
+			if o == nil || !input.filter.match(pathpkg.Base(o.Path), o) {
+				continue
+			}
+			objects = append(objects, o)
+		}
+		// Iterator doesn't allow an empty page before done.
+		if len(objects) > 0 {
+			l.mu.Lock()
+			l.pages = append(l.pages, objects)
+			l.mu.Unlock()
+
+			select {
+			case l.ready <- struct{}{}:
+			default:
+			}
+		}
+
+		if output.NextMarker == nil || *output.NextMarker == "" {
+			return nil
+		}
+		input.marker = output.NextMarker
+	}
+}
+
+// shard will return the status to list dir with the options of input.
+func (i *objectPageStatus) shard(dir, prefix string) *objectPageStatus {
+	return &objectPageStatus{
+		maxResults:      i.maxResults,
+		prefix:          prefix,
+		timeout:         i.timeout,
+		clientRequestID: i.clientRequestID,
+		hasCallOptions:  i.hasCallOptions,
+		callOptions:     i.callOptions,
+		extendedInfo:    i.extendedInfo,
+		filter:          i.filter,
+		dir:             dir,
+	}
+}
+
+func (s *Storage) nextObjectPageParallel(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	// Workers are started by the first page, so that they run with the ctx of iterator.
+	if input.parallel == nil {
+		input.parallel = s.startParallelList(ctx, input, input.concurrency)
+	}
+
+	l := input.parallel
+	for {
+		l.mu.Lock()
+		if len(l.pages) > 0 {
+			page.Data = append(page.Data, l.pages[0]...)
+			l.pages = l.pages[1:]
+			l.mu.Unlock()
+			return nil
+		}
+		done, err := l.done, l.err
+		l.mu.Unlock()
+
+		if done {
+			if err != nil {
+				return err
+			}
+			return types.IterateDone
+		}
+
+		select {
+		case <-l.ready:
+		case <-ctx.Done():
+			l.cancel()
+			return ctx.Err()
+		}
+	}
+}
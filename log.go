@@ -0,0 +1,67 @@
+package azfile
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// RequestLog is the log of a request sent to service.
+type RequestLog struct {
+	Method string
+	// URL is the url of the request, the signature of SAS will be redacted.
+	URL string
+	// StatusCode will be 0 if no response is received.
+	StatusCode int
+	Latency    time.Duration
+	// RequestID is the x-ms-request-id returned by service.
+	RequestID string
+	Err       error
+}
+
+// RequestLogger will be called after every try of requests.
+type RequestLogger func(log RequestLog)
+
+const redactedValue = "REDACTED"
+
+type requestLogPolicy struct {
+	logger RequestLogger
+}
+
+// Do implements policy.Policy
+func (p requestLogPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	start := time.Now()
+
+	resp, err := req.Next()
+
+	log := RequestLog{
+		Method:  raw.Method,
+		URL:     redactURL(raw.URL),
+		Latency: time.Since(start),
+		Err:     err,
+	}
+	if resp != nil {
+		log.StatusCode = resp.StatusCode
+		log.RequestID = resp.Header.Get("x-ms-request-id")
+	}
+	p.logger(log)
+
+	return resp, err
+}
+
+// redactURL will hide the signature of SAS in the url.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	if q.Get("sig") == "" {
+		return u.String()
+	}
+
+	q.Set("sig", redactedValue)
+
+	cu := *u
+	cu.RawQuery = q.Encode()
+	return cu.String()
+}
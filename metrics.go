@@ -0,0 +1,99 @@
+package azfile
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// MetricsCollector receives the metrics of requests sent to service, it could be
+// adapted to Prometheus, statsd and so on.
+//
+// The methods will be called concurrently, and should not block.
+type MetricsCollector interface {
+	// AddCounter will add delta to the counter.
+	AddCounter(name string, delta float64, labels map[string]string)
+	// ObserveHistogram will add an observation to the histogram.
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+// Names of the metrics.
+//
+// All metrics carry the labels `op`, `method`, `status` and `code`:
+//   - `op` is the storage operation which sends the request, like `read` and `write`,
+//     or `other` if the operation is not instrumented.
+//   - `status` is the HTTP status code, or `0` if no response is received.
+//   - `code` is the x-ms-error-code returned by service, or empty if succeeded.
+const (
+	// MetricRequests is the counter of requests, every retry counts.
+	MetricRequests = "azfile_requests_total"
+	// MetricErrors is the counter of failed requests.
+	MetricErrors = "azfile_errors_total"
+	// MetricRequestDuration is the histogram of request latency in seconds.
+	MetricRequestDuration = "azfile_request_duration_seconds"
+	// MetricBytesRead is the counter of bytes downloaded from files.
+	MetricBytesRead = "azfile_read_bytes_total"
+	// MetricBytesWritten is the counter of bytes uploaded into files.
+	MetricBytesWritten = "azfile_written_bytes_total"
+)
+
+// metricsOtherOp is the op label of requests sent outside of instrumented operations.
+const metricsOtherOp = "other"
+
+type operationKey struct{}
+
+// withOperation will carry the operation name in ctx, so that requests could be
+// labeled by the operation.
+func withOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// metricsPolicy will report the metrics of every try of requests.
+type metricsPolicy struct {
+	collector MetricsCollector
+}
+
+// Do implements policy.Policy
+func (p metricsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	start := time.Now()
+
+	resp, err := req.Next()
+
+	labels := map[string]string{
+		"op":     metricsOtherOp,
+		"method": raw.Method,
+		"status": "0",
+		"code":   "",
+	}
+	if op, ok := raw.Context().Value(operationKey{}).(string); ok {
+		labels["op"] = op
+	}
+	if resp != nil {
+		labels["status"] = strconv.Itoa(resp.StatusCode)
+		labels["code"] = resp.Header.Get("x-ms-error-code")
+	}
+
+	p.collector.AddCounter(MetricRequests, 1, labels)
+	p.collector.ObserveHistogram(MetricRequestDuration, time.Since(start).Seconds(), labels)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		p.collector.AddCounter(MetricErrors, 1, labels)
+		return resp, err
+	}
+
+	q := raw.URL.Query()
+	switch {
+	case raw.Method == http.MethodPut && q.Get("comp") == "range" && raw.ContentLength > 0:
+		// Put Range carries the content in body, clear range doesn't.
+		p.collector.AddCounter(MetricBytesWritten, float64(raw.ContentLength), labels)
+	case raw.Method == http.MethodGet && q.Get("comp") == "" && q.Get("restype") == "" && resp.ContentLength > 0:
+		// Only Get File has neither comp nor restype, the body is counted before
+		// it's actually read by caller.
+		p.collector.AddCounter(MetricBytesRead, float64(resp.ContentLength), labels)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,52 @@
+package azfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+// PathMapper maps the paths of files used by callers to the paths stored in the share,
+// like hashing the long names or adding prefixes sharded by date, so that the paths
+// fit the limits of Azure Files.
+//
+// Only the paths of files relative to the work dir are mapped, the leading "/" is trimmed
+// before mapped. Directories are used as is.
+type PathMapper interface {
+	// MapPath will return the path to store the file of path.
+	MapPath(path string) string
+	// UnmapPath will return the path of the file listed at the stored path, ok is false
+	// if the file is not stored by MapPath, and it will be skipped by List.
+	UnmapPath(stored string) (path string, ok bool)
+}
+
+// mapPath will return the stored path of the file, the path is sanitized after mapped.
+func (s *Storage) mapPath(path string) string {
+	if s.pathMapper != nil && strings.TrimPrefix(path, "/") != "" {
+		path = s.pathMapper.MapPath(strings.TrimPrefix(path, "/"))
+	}
+	return s.normalizePath(path)
+}
+
+// unmapPath will return the path of the file listed at the stored path.
+func (s *Storage) unmapPath(stored string) (string, bool) {
+	if s.pathMapper == nil {
+		return stored, true
+	}
+	return s.pathMapper.UnmapPath(stored)
+}
+
+// getAbsFilePath will return the abs path of the file stored.
+func (s *Storage) getAbsFilePath(path string) string {
+	return s.getAbsPath(s.mapPath(path))
+}
+
+// checkPathMapper will reject the operations walking directories with the path mapper,
+// which pass the stored paths listed to the operations of files.
+func (s *Storage) checkPathMapper(op string) error {
+	if s.pathMapper != nil {
+		return fmt.Errorf("%w: %s with path mapper", services.ErrCapabilityInsufficient, op)
+	}
+	return nil
+}
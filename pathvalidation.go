@@ -0,0 +1,153 @@
+package azfile
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Modes of path_validation.
+const (
+	// PathValidationReject will reject the paths invalid for Azure Files with ErrInvalidPath.
+	PathValidationReject = "reject"
+	// PathValidationSanitize will replace the invalid characters and names, so that
+	// the paths are valid. Paths too long or too deep are still rejected.
+	PathValidationSanitize = "sanitize"
+)
+
+// Limits of paths in Azure Files.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-shares--directories--files--and-metadata
+const (
+	maxPathComponentLength = 255
+	maxPathLength          = 2048
+	maxPathDepth           = 250
+
+	// invalidPathChars are not allowed in the names of directories and files.
+	invalidPathChars = "\"\\:|<>*?"
+)
+
+// reservedNames are the device names of Windows which are not allowed as names.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true, "CLOCK$": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func parsePathValidation(has bool, v string) (string, error) {
+	if !has {
+		return "", nil
+	}
+	if v != PathValidationReject && v != PathValidationSanitize {
+		return "", fmt.Errorf("path validation %s is not supported", v)
+	}
+	return v, nil
+}
+
+// checkPath will validate the path to be created before any request sent.
+//
+// The absolute path has been sanitized by getAbsPath in sanitize mode, so only the
+// limits which could not be sanitized are failed.
+func (s *Storage) checkPath(path string) error {
+	if s.pathValidation == "" {
+		return nil
+	}
+	return validatePath(s.getAbsFilePath(path), s.allowTrailingDot)
+}
+
+// normalizePath will sanitize the path in sanitize mode, so that the same path is
+// mapped to the same name by all operations.
+func (s *Storage) normalizePath(path string) string {
+	if s.pathValidation != PathValidationSanitize {
+		return path
+	}
+	return sanitizePath(path, s.allowTrailingDot)
+}
+
+// validatePath will check the path relative to the root of share, names ending with
+// dot are valid if allowTrailingDot is set.
+func validatePath(path string, allowTrailingDot bool) error {
+	if n := utf8.RuneCountInString(path); n > maxPathLength {
+		return fmt.Errorf("%w: path is %d characters, longer than %d", ErrInvalidPath, n, maxPathLength)
+	}
+
+	var depth int
+	for _, v := range strings.Split(path, "/") {
+		if v == "" || v == "." {
+			continue
+		}
+		depth++
+
+		// Names of dots only are the relative references.
+		if strings.Trim(v, ".") == "" {
+			return fmt.Errorf("%w: name %q is not allowed", ErrInvalidPath, v)
+		}
+		if n := utf8.RuneCountInString(v); n > maxPathComponentLength {
+			return fmt.Errorf("%w: name is %d characters, longer than %d", ErrInvalidPath, n, maxPathComponentLength)
+		}
+		for _, c := range v {
+			if c < 0x20 || strings.ContainsRune(invalidPathChars, c) {
+				return fmt.Errorf("%w: name %q contains invalid character %q", ErrInvalidPath, v, c)
+			}
+		}
+		if strings.HasSuffix(v, " ") {
+			return fmt.Errorf("%w: name %q ends with space", ErrInvalidPath, v)
+		}
+		if !allowTrailingDot && strings.HasSuffix(v, ".") {
+			return fmt.Errorf("%w: name %q ends with dot", ErrInvalidPath, v)
+		}
+		if reservedNames[strings.ToUpper(v)] {
+			return fmt.Errorf("%w: name %q is reserved", ErrInvalidPath, v)
+		}
+	}
+	// The file is in the last level of directories.
+	if depth-1 > maxPathDepth {
+		return fmt.Errorf("%w: path is %d levels deep, deeper than %d", ErrInvalidPath, depth-1, maxPathDepth)
+	}
+	return nil
+}
+
+// sanitizePath will make every name in path valid, the valid names are kept as is.
+func sanitizePath(path string, allowTrailingDot bool) string {
+	names := strings.Split(path, "/")
+	for i, v := range names {
+		// Empty names are the leading and trailing slashes.
+		if v != "" && v != "." {
+			names[i] = sanitizeName(v, allowTrailingDot)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+func sanitizeName(name string, allowTrailingDot bool) string {
+	// Names ending with dot are kept with allow_trailing_dot, except the names of
+	// dots only.
+	trimmed := " ."
+	if allowTrailingDot {
+		trimmed = " "
+	}
+
+	name = strings.Map(func(c rune) rune {
+		if c < 0x20 || strings.ContainsRune(invalidPathChars, c) {
+			return '_'
+		}
+		return c
+	}, name)
+
+	name = strings.TrimRight(name, trimmed)
+	if strings.Trim(name, ".") == "" {
+		return "_"
+	}
+	if reservedNames[strings.ToUpper(name)] {
+		name += "_"
+	}
+
+	if utf8.RuneCountInString(name) > maxPathComponentLength {
+		name = string([]rune(name)[:maxPathComponentLength])
+		// The truncated name could end with space or dot again.
+		name = strings.TrimRight(name, trimmed)
+	}
+	return name
+}
@@ -0,0 +1,212 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/share"
+)
+
+// maxAccessPolicies is the maximum number of stored access policies of a share.
+const maxAccessPolicies = 5
+
+// AccessPolicy is a stored access policy of the share, the SAS bound to it will be
+// revoked once it's deleted.
+type AccessPolicy struct {
+	// ID is the unique identifier of the policy, up to 64 characters.
+	ID string
+	// Start and Expiry are optional, zero means not set.
+	Start  time.Time
+	Expiry time.Time
+	// Permissions is the permissions granted in the order of "rcwdl", like "rl".
+	Permissions string
+}
+
+// GetAccessPolicies will list the stored access policies of the share.
+//
+// This function will create a context by default.
+func (s *Service) GetAccessPolicies(shareName string) (policies []AccessPolicy, err error) {
+	return s.GetAccessPoliciesWithContext(context.Background(), shareName)
+}
+
+// GetAccessPoliciesWithContext will list the stored access policies of the share.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/get-share-acl
+func (s *Service) GetAccessPoliciesWithContext(ctx context.Context, shareName string) (policies []AccessPolicy, err error) {
+	defer func() {
+		err = s.formatError("get_access_policies", err, shareName)
+	}()
+
+	return s.getAccessPolicies(ctx, shareName)
+}
+
+func (s *Service) getAccessPolicies(ctx context.Context, shareName string) (policies []AccessPolicy, err error) {
+	output, err := s.service.NewShareClient(shareName).GetAccessPolicy(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range output.SignedIdentifiers {
+		p := AccessPolicy{ID: deref(v.ID)}
+		if v.AccessPolicy != nil {
+			p.Start = deref(v.AccessPolicy.Start)
+			p.Expiry = deref(v.AccessPolicy.Expiry)
+			p.Permissions = deref(v.AccessPolicy.Permission)
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// SetAccessPolicy will add the stored access policy to the share, or replace the one with the same ID.
+//
+// This function will create a context by default.
+func (s *Service) SetAccessPolicy(shareName string, policy AccessPolicy) (err error) {
+	return s.SetAccessPolicyWithContext(context.Background(), shareName, policy)
+}
+
+// SetAccessPolicyWithContext will add the stored access policy to the share, or replace
+// the one with the same ID.
+//
+// The policies are read and written back as a whole, so the policies changed by others
+// at the same time could be lost. A share could have at most 5 policies.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-share-acl
+func (s *Service) SetAccessPolicyWithContext(ctx context.Context, shareName string, policy AccessPolicy) (err error) {
+	defer func() {
+		err = s.formatError("set_access_policy", err, shareName)
+	}()
+
+	policies, err := s.getAccessPolicies(ctx, shareName)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, v := range policies {
+		if v.ID == policy.ID {
+			policies[i] = policy
+			replaced = true
+		}
+	}
+	if !replaced {
+		if len(policies) >= maxAccessPolicies {
+			return fmt.Errorf("share %s already has %d access policies", shareName, maxAccessPolicies)
+		}
+		policies = append(policies, policy)
+	}
+
+	return s.setAccessPolicies(ctx, shareName, policies)
+}
+
+// DeleteAccessPolicy will delete the stored access policy from the share.
+//
+// This function will create a context by default.
+func (s *Service) DeleteAccessPolicy(shareName string, id string) (err error) {
+	return s.DeleteAccessPolicyWithContext(context.Background(), shareName, id)
+}
+
+// DeleteAccessPolicyWithContext will delete the stored access policy from the share, all
+// SAS bound to it will be revoked. Deleting a policy that doesn't exist is not an error.
+func (s *Service) DeleteAccessPolicyWithContext(ctx context.Context, shareName string, id string) (err error) {
+	defer func() {
+		err = s.formatError("delete_access_policy", err, shareName)
+	}()
+
+	policies, err := s.getAccessPolicies(ctx, shareName)
+	if err != nil {
+		return err
+	}
+
+	kept := policies[:0]
+	for _, v := range policies {
+		if v.ID != id {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == len(policies) {
+		return nil
+	}
+
+	return s.setAccessPolicies(ctx, shareName, kept)
+}
+
+func (s *Service) setAccessPolicies(ctx context.Context, shareName string, policies []AccessPolicy) error {
+	acl := make([]*share.SignedIdentifier, 0, len(policies))
+	for _, v := range policies {
+		ap := &share.AccessPolicy{}
+		if !v.Start.IsZero() {
+			ap.Start = to.Ptr(v.Start.UTC())
+		}
+		if !v.Expiry.IsZero() {
+			ap.Expiry = to.Ptr(v.Expiry.UTC())
+		}
+		if v.Permissions != "" {
+			ap.Permission = to.Ptr(v.Permissions)
+		}
+		acl = append(acl, &share.SignedIdentifier{
+			ID:           to.Ptr(v.ID),
+			AccessPolicy: ap,
+		})
+	}
+
+	_, err := s.service.NewShareClient(shareName).SetAccessPolicy(ctx, &share.SetAccessPolicyOptions{
+		ShareACL: acl,
+	})
+	return err
+}
+
+// SignPolicySAS will return a SAS token bound to the stored access policy of the share.
+//
+// path is the path of a file relative to the root of share, the token grants the access
+// of the whole share if it's empty. The permissions and expiry are defined by the policy,
+// so the token will be revoked once the policy is deleted or changed. The token doesn't
+// start with "?".
+func (s *Service) SignPolicySAS(shareName string, path string, id string) (token string, err error) {
+	defer func() {
+		err = s.formatError("sign_policy_sas", err, shareName)
+	}()
+
+	if s.sharedKey == nil {
+		return "", ErrSharedKeyRequired
+	}
+
+	values := sas.SignatureValues{
+		Protocol:   sas.ProtocolHTTPS,
+		ShareName:  shareName,
+		FilePath:   path,
+		Identifier: id,
+	}
+	qp, err := values.SignWithSharedKey(s.sharedKey)
+	if err != nil {
+		return "", err
+	}
+	return qp.Encode(), nil
+}
+
+// SignAccountSAS will return an account SAS token of the file service.
+//
+// The token doesn't start with "?", and it could not be revoked except by rotating the
+// account key, consider SignPolicySAS for revocable tokens.
+func (s *Service) SignAccountSAS(resources sas.AccountResourceTypes, permissions sas.AccountPermissions, expire time.Duration) (token string, err error) {
+	defer func() {
+		err = s.formatError("sign_account_sas", err, "")
+	}()
+
+	u, err := s.service.GetSASURL(resources, permissions, time.Now().UTC().Add(expire), nil)
+	if errors.Is(err, fileerror.MissingSharedKeyCredential) {
+		return "", ErrSharedKeyRequired
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, token, _ = strings.Cut(u, "?")
+	return token, nil
+}
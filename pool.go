@@ -0,0 +1,70 @@
+package azfile
+
+import (
+	"context"
+	"sync"
+)
+
+// workerPool runs tasks with at most concurrency goroutines at the same time.
+//
+// The context returned by newWorkerPool will be canceled once any task failed,
+// and the first error will be returned by Wait.
+type workerPool struct {
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	errOnce sync.Once
+	err     error
+}
+
+func newWorkerPool(ctx context.Context, concurrency int) (*workerPool, context.Context) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &workerPool{
+		sem:    make(chan struct{}, concurrency),
+		cancel: cancel,
+	}, ctx
+}
+
+// Go will block until there is an idle worker, and run fn on it.
+//
+// Go returns false without running fn if the pool has been canceled.
+func (p *workerPool) Go(ctx context.Context, fn func() error) bool {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		p.setError(ctx.Err())
+		return false
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+
+		if err := fn(); err != nil {
+			p.setError(err)
+		}
+	}()
+	return true
+}
+
+// Wait will wait for all running tasks, and return the first error.
+func (p *workerPool) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	return p.err
+}
+
+func (p *workerPool) setError(err error) {
+	p.errOnce.Do(func() {
+		p.err = err
+		p.cancel()
+	})
+}
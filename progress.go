@@ -0,0 +1,65 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Progress is the accumulated progress of an operation.
+//
+// io_callback reports the content read or written, while Progress also covers
+// the operations without content transferred by us, like list, delete and server-side copy.
+type Progress struct {
+	// Objects is the number of objects which have been processed.
+	Objects int64
+	// Bytes is the number of bytes which have been transferred, including the bytes
+	// copied by service.
+	Bytes int64
+}
+
+// ProgressFunc will be called every time the progress of the operation changed.
+type ProgressFunc func(p Progress)
+
+// withListProgress will report the number of listed objects after every page.
+func withListProgress(next types.NextObjectFunc, fn ProgressFunc) types.NextObjectFunc {
+	var listed int64
+	return func(ctx context.Context, page *types.ObjectPage) error {
+		err := next(ctx, page)
+		if len(page.Data) > 0 {
+			listed += int64(len(page.Data))
+			fn(Progress{Objects: listed})
+		}
+		return err
+	}
+}
+
+// withCopyProgress will report the bytes copied by service while the copy is pending.
+//
+// The returned func is nil if both fn and progress are nil.
+func withCopyProgress(fn CopyProgressFunc, progress ProgressFunc) CopyProgressFunc {
+	if progress == nil {
+		return fn
+	}
+	return func(status CopyStatus) {
+		if fn != nil {
+			fn(status)
+		}
+		progress(Progress{Bytes: status.CopiedBytes})
+	}
+}
+
+// reportCopied will report the copied file after the copy finished.
+func (s *Storage) reportCopied(ctx context.Context, path string, progress ProgressFunc) error {
+	output, err := s.fileClient(path).GetProperties(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	if output.ContentLength != nil {
+		size = *output.ContentLength
+	}
+	progress(Progress{Objects: 1, Bytes: size})
+	return nil
+}
@@ -0,0 +1,194 @@
+package azfile
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+)
+
+// ServiceProperties is the properties of the file service in the storage account.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-file-service-properties
+type ServiceProperties struct {
+	// HourMetrics and MinuteMetrics will be kept unchanged while setting if they are nil.
+	HourMetrics   *Metrics
+	MinuteMetrics *Metrics
+	// CORS will be kept unchanged while setting if it's nil.
+	CORS []CORSRule
+	// SMBMultichannel will be kept unchanged while setting if it's nil.
+	//
+	// SMB multichannel is only available for premium FileStorage accounts.
+	SMBMultichannel *bool
+}
+
+// Metrics is the settings of Storage Analytics metrics.
+type Metrics struct {
+	Enabled bool
+	// IncludeAPIs will generate summary statistics for called API operations.
+	IncludeAPIs bool
+	// RetentionDays is the days the metrics data will be kept, 0 means the data will be kept forever.
+	RetentionDays int32
+}
+
+// CORSRule is a CORS rule of the file service.
+type CORSRule struct {
+	AllowedOrigins  []string
+	AllowedMethods  []string
+	AllowedHeaders  []string
+	ExposedHeaders  []string
+	MaxAgeInSeconds int32
+}
+
+// metricsVersion is the version of Storage Analytics to configure.
+const metricsVersion = "1.0"
+
+// GetServiceProperties will get the properties of the file service.
+//
+// This function will create a context by default.
+func (s *Service) GetServiceProperties() (props *ServiceProperties, err error) {
+	return s.GetServicePropertiesWithContext(context.Background())
+}
+
+// GetServicePropertiesWithContext will get the properties of the file service.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/get-file-service-properties
+func (s *Service) GetServicePropertiesWithContext(ctx context.Context) (props *ServiceProperties, err error) {
+	defer func() {
+		err = s.formatError("get_service_properties", err, "")
+	}()
+
+	return getServiceProperties(ctx, s.service)
+}
+
+// SetServiceProperties will set the properties of the file service.
+//
+// This function will create a context by default.
+func (s *Service) SetServiceProperties(props ServiceProperties) (err error) {
+	return s.SetServicePropertiesWithContext(context.Background(), props)
+}
+
+// SetServicePropertiesWithContext will set the properties of the file service, the
+// properties which are nil will be kept unchanged.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-file-service-properties
+func (s *Service) SetServicePropertiesWithContext(ctx context.Context, props ServiceProperties) (err error) {
+	defer func() {
+		err = s.formatError("set_service_properties", err, "")
+	}()
+
+	return setServiceProperties(ctx, s.service, props)
+}
+
+func getServiceProperties(ctx context.Context, client *service.Client) (*ServiceProperties, error) {
+	output, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	props := &ServiceProperties{
+		HourMetrics:   parseMetrics(output.HourMetrics),
+		MinuteMetrics: parseMetrics(output.MinuteMetrics),
+	}
+	for _, v := range output.CORS {
+		if v == nil {
+			continue
+		}
+		props.CORS = append(props.CORS, parseCORSRule(v))
+	}
+	if p := output.Protocol; p != nil && p.Smb != nil && p.Smb.Multichannel != nil {
+		props.SMBMultichannel = p.Smb.Multichannel.Enabled
+	}
+	return props, nil
+}
+
+func setServiceProperties(ctx context.Context, client *service.Client, props ServiceProperties) error {
+	options := &service.SetPropertiesOptions{
+		HourMetrics:   formatMetrics(props.HourMetrics),
+		MinuteMetrics: formatMetrics(props.MinuteMetrics),
+	}
+	for _, v := range props.CORS {
+		options.CORS = append(options.CORS, formatCORSRule(v))
+	}
+	if props.SMBMultichannel != nil {
+		options.Protocol = &service.ProtocolSettings{
+			Smb: &service.SMBSettings{
+				Multichannel: &service.SMBMultichannel{Enabled: props.SMBMultichannel},
+			},
+		}
+	}
+
+	_, err := client.SetProperties(ctx, options)
+	return err
+}
+
+func parseMetrics(v *service.Metrics) *Metrics {
+	if v == nil {
+		return nil
+	}
+
+	m := &Metrics{}
+	if v.Enabled != nil {
+		m.Enabled = *v.Enabled
+	}
+	if v.IncludeAPIs != nil {
+		m.IncludeAPIs = *v.IncludeAPIs
+	}
+	if r := v.RetentionPolicy; r != nil && r.Enabled != nil && *r.Enabled && r.Days != nil {
+		m.RetentionDays = *r.Days
+	}
+	return m
+}
+
+func formatMetrics(m *Metrics) *service.Metrics {
+	if m == nil {
+		return nil
+	}
+
+	v := &service.Metrics{
+		Enabled: to.Ptr(m.Enabled),
+		Version: to.Ptr(metricsVersion),
+		RetentionPolicy: &service.RetentionPolicy{
+			Enabled: to.Ptr(m.RetentionDays > 0),
+		},
+	}
+	// IncludeAPIs must not be set while metrics is disabled.
+	if m.Enabled {
+		v.IncludeAPIs = to.Ptr(m.IncludeAPIs)
+	}
+	if m.RetentionDays > 0 {
+		v.RetentionPolicy.Days = to.Ptr(m.RetentionDays)
+	}
+	return v
+}
+
+func parseCORSRule(v *service.CORSRule) CORSRule {
+	split := func(s *string) []string {
+		if s == nil || *s == "" {
+			return nil
+		}
+		return strings.Split(*s, ",")
+	}
+
+	r := CORSRule{
+		AllowedOrigins: split(v.AllowedOrigins),
+		AllowedMethods: split(v.AllowedMethods),
+		AllowedHeaders: split(v.AllowedHeaders),
+		ExposedHeaders: split(v.ExposedHeaders),
+	}
+	if v.MaxAgeInSeconds != nil {
+		r.MaxAgeInSeconds = *v.MaxAgeInSeconds
+	}
+	return r
+}
+
+func formatCORSRule(r CORSRule) *service.CORSRule {
+	return &service.CORSRule{
+		AllowedOrigins:  to.Ptr(strings.Join(r.AllowedOrigins, ",")),
+		AllowedMethods:  to.Ptr(strings.Join(r.AllowedMethods, ",")),
+		AllowedHeaders:  to.Ptr(strings.Join(r.AllowedHeaders, ",")),
+		ExposedHeaders:  to.Ptr(strings.Join(r.ExposedHeaders, ",")),
+		MaxAgeInSeconds: to.Ptr(r.MaxAgeInSeconds),
+	}
+}
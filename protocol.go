@@ -0,0 +1,86 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+)
+
+// ProtocolSettings is the protocol settings of the file service in the storage account.
+//
+// Only SMB multichannel could be configured by the file REST API. The allowed SMB
+// versions, authentication methods and channel encryption are properties of the
+// storage account resource, which are managed by Azure Resource Manager instead.
+//
+// It's a view of the SMB multichannel of ServiceProperties, which is read and written
+// by the same accessors, so that the protocol settings could also be read by Storage
+// without the metrics and CORS rules.
+type ProtocolSettings struct {
+	// SMBMultichannel is only available for premium FileStorage accounts.
+	SMBMultichannel bool
+}
+
+// GetProtocolSettings will get the protocol settings of the file service.
+//
+// This function will create a context by default.
+func (s *Service) GetProtocolSettings() (settings *ProtocolSettings, err error) {
+	return s.GetProtocolSettingsWithContext(context.Background())
+}
+
+// GetProtocolSettingsWithContext will get the protocol settings of the file service.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/get-file-service-properties
+func (s *Service) GetProtocolSettingsWithContext(ctx context.Context) (settings *ProtocolSettings, err error) {
+	defer func() {
+		err = s.formatError("get_protocol_settings", err, "")
+	}()
+
+	return getProtocolSettings(ctx, s.service)
+}
+
+// SetProtocolSettings will set the protocol settings of the file service.
+//
+// This function will create a context by default.
+func (s *Service) SetProtocolSettings(settings ProtocolSettings) (err error) {
+	return s.SetProtocolSettingsWithContext(context.Background(), settings)
+}
+
+// SetProtocolSettingsWithContext will set the protocol settings of the file service,
+// other properties like metrics and CORS are kept unchanged.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-file-service-properties
+func (s *Service) SetProtocolSettingsWithContext(ctx context.Context, settings ProtocolSettings) (err error) {
+	defer func() {
+		err = s.formatError("set_protocol_settings", err, "")
+	}()
+
+	return setServiceProperties(ctx, s.service, ServiceProperties{
+		SMBMultichannel: to.Ptr(settings.SMBMultichannel),
+	})
+}
+
+// GetProtocolSettings will get the protocol settings of the file service which the share belongs to.
+//
+// This function will create a context by default.
+func (s *Storage) GetProtocolSettings() (settings *ProtocolSettings, err error) {
+	return s.GetProtocolSettingsWithContext(context.Background())
+}
+
+// GetProtocolSettingsWithContext will get the protocol settings of the file service
+// which the share belongs to, the credential must be authorized for the account.
+func (s *Storage) GetProtocolSettingsWithContext(ctx context.Context) (settings *ProtocolSettings, err error) {
+	defer func() {
+		err = s.formatError("get_protocol_settings", err)
+	}()
+
+	return getProtocolSettings(ctx, s.service)
+}
+
+func getProtocolSettings(ctx context.Context, client *service.Client) (*ProtocolSettings, error) {
+	props, err := getServiceProperties(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return &ProtocolSettings{SMBMultichannel: deref(props.SMBMultichannel)}, nil
+}
@@ -0,0 +1,95 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// Range is a valid byte range of a file.
+type Range struct {
+	Offset int64
+	Size   int64
+}
+
+// ListRanges will list the valid ranges of the file in [offset, offset+size).
+//
+// size == 0 means till the end of the file.
+//
+// This function will create a context by default.
+func (s *Storage) ListRanges(path string, offset, size int64) (ranges []Range, err error) {
+	return s.ListRangesWithContext(context.Background(), path, offset, size)
+}
+
+// ListRangesWithContext will list the valid ranges of the file in [offset, offset+size).
+//
+// size == 0 means till the end of the file.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/list-ranges
+func (s *Storage) ListRangesWithContext(ctx context.Context, path string, offset, size int64) (ranges []Range, err error) {
+	defer func() {
+		err = s.formatError("list_ranges", err, path)
+	}()
+
+	output, err := s.fileClient(path).GetRangeList(ctx, &file.GetRangeListOptions{
+		Range: file.HTTPRange{Offset: offset, Count: size},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ranges = make([]Range, 0, len(output.Ranges))
+	for _, v := range output.Ranges {
+		ranges = append(ranges, Range{
+			Offset: *v.Start,
+			Size:   *v.End - *v.Start + 1,
+		})
+	}
+	return ranges, nil
+}
+
+// Resize will change the size of the file, the file will be truncated or extended with zeros.
+//
+// This function will create a context by default.
+func (s *Storage) Resize(path string, size int64) (err error) {
+	return s.ResizeWithContext(context.Background(), path, size)
+}
+
+// ResizeWithContext will change the size of the file, the file will be truncated or extended with zeros.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-file-properties
+func (s *Storage) ResizeWithContext(ctx context.Context, path string, size int64) (err error) {
+	defer func() {
+		err = s.formatError("resize", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err = s.fileClient(path).Resize(ctx, size, nil)
+	return err
+}
+
+// ClearRange will clear the range [offset, offset+size) of the file and release the space used by it.
+//
+// This function will create a context by default.
+func (s *Storage) ClearRange(path string, offset, size int64) (err error) {
+	return s.ClearRangeWithContext(context.Background(), path, offset, size)
+}
+
+// ClearRangeWithContext will clear the range [offset, offset+size) of the file and release the space used by it.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/put-range
+func (s *Storage) ClearRangeWithContext(ctx context.Context, path string, offset, size int64) (err error) {
+	defer func() {
+		err = s.formatError("clear_range", err, path)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err = s.fileClient(path).ClearRange(ctx, file.HTTPRange{Offset: offset, Count: size}, nil)
+	return err
+}
@@ -0,0 +1,246 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+
+	"github.com/beyondstorage/go-storage/v4/pkg/iowrap"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// ReadStream will return a reader of the file's content.
+//
+// This function will create a context by default.
+func (s *Storage) ReadStream(path string, pairs ...types.Pair) (rc io.ReadCloser, err error) {
+	return s.ReadStreamWithContext(context.Background(), path, pairs...)
+}
+
+// ReadStreamWithContext will return a reader of the file's content, which must be closed
+// after used.
+//
+// The pairs of Read except concurrency and verify_content_md5 are supported, timeout
+// covers the whole lifetime of the reader. The request is issued before returned, so that
+// errors like not found are returned here. If the body is broken while reading, the rest
+// content will be requested from the last offset again up to read_retries times, and the
+// reader fails with ErrConditionNotMet if the file has been changed since opened.
+func (s *Storage) ReadStreamWithContext(ctx context.Context, path string, pairs ...types.Pair) (rc io.ReadCloser, err error) {
+	defer func() {
+		err = s.formatError("read_stream", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Read...)
+	opt, err := s.parsePairStorageRead(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every segment of encrypted file must be opened in order.
+	if s.encryptionKey != nil {
+		return nil, fmt.Errorf("%w: read stream while client-side encryption enabled", services.ErrCapabilityInsufficient)
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+	}
+	err = s.checkConditions(ctx, path, cond)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r, err := s.newReadStream(ctx, path, opt)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r.cancel = cancel
+
+	rc = r
+	if s.limiter != nil || opt.HasIoCallback {
+		rd := s.limitReader(ctx, r)
+		if opt.HasIoCallback {
+			rd = iowrap.CallbackReader(rd, opt.IoCallback)
+		}
+		rc = readCloser{Reader: rd, Closer: r}
+	}
+	return formattedReadCloser{rc: rc, s: s, path: path}, nil
+}
+
+// readStream reads the file with ranged requests, a new request is issued from the
+// last offset once the body is broken.
+type readStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *file.Client
+
+	// offset is the position of the next byte to read.
+	offset int64
+	// remaining is the number of bytes left to read, it's only used if hasCount is true,
+	// otherwise the file will be read till the end.
+	hasCount  bool
+	remaining int64
+	// retries is the number of times left the content could be requested again, which
+	// is shared by all reads of the stream.
+	retries int
+	// etag is the ETag of the file when the first request responded, the content is
+	// requested again only if the file still matches it.
+	etag *azcore.ETag
+
+	body io.ReadCloser
+	// err is returned by all reads after the stream failed.
+	err error
+}
+
+// newReadStream will create a readStream with the offset, size and read_retries of opt,
+// the first request is issued before returned.
+func (s *Storage) newReadStream(ctx context.Context, path string, opt pairStorageRead) (*readStream, error) {
+	r := &readStream{
+		ctx:      ctx,
+		cancel:   func() {},
+		client:   s.fileClient(path),
+		hasCount: opt.HasSize && opt.Size > 0,
+		retries:  parseReadRetries(opt.HasReadRetries, opt.ReadRetries),
+	}
+	if opt.HasOffset {
+		r.offset = opt.Offset
+	}
+	if r.hasCount {
+		r.remaining = opt.Size
+	}
+
+	err := r.open()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *readStream) Read(p []byte) (n int, err error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for {
+		if r.body == nil {
+			err = r.open()
+			if err != nil {
+				if err != io.EOF {
+					r.err = err
+				}
+				return 0, err
+			}
+		}
+
+		n, err = r.body.Read(p)
+		r.offset += int64(n)
+		if r.hasCount {
+			r.remaining -= int64(n)
+		}
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+
+		// The body is broken, the rest content will be requested again.
+		_ = r.body.Close()
+		r.body = nil
+		if r.retries <= 0 || r.ctx.Err() != nil {
+			r.err = err
+			return n, err
+		}
+		r.retries--
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// open will request the content from offset, io.EOF is returned if there is nothing left.
+func (r *readStream) open() error {
+	if r.hasCount && r.remaining <= 0 {
+		return io.EOF
+	}
+
+	var count int64
+	if r.hasCount {
+		count = r.remaining
+	}
+	// The SDK doesn't expose the conditions of Get File, so If-Match is set by header.
+	ctx := r.ctx
+	if r.etag != nil {
+		ctx = policy.WithHTTPHeader(ctx, http.Header{"If-Match": []string{string(*r.etag)}})
+	}
+	output, err := r.client.DownloadStream(ctx, &file.DownloadStreamOptions{
+		Range: file.HTTPRange{Offset: r.offset, Count: count},
+	})
+	if err != nil {
+		// The body could be broken after all content has been read.
+		if r.etag != nil && fileerror.HasCode(err, fileerror.InvalidRange) {
+			return io.EOF
+		}
+		// The file has been changed since opened.
+		if fileerror.HasCode(err, fileerror.ConditionNotMet) {
+			return fmt.Errorf("%w: file has been changed while reading", ErrConditionNotMet)
+		}
+		return err
+	}
+
+	if r.etag == nil {
+		r.etag = output.ETag
+	}
+	r.body = output.Body
+	return nil
+}
+
+func (r *readStream) Close() error {
+	defer r.cancel()
+
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// formattedReadCloser will format the errors of rc except io.EOF.
+type formattedReadCloser struct {
+	rc   io.ReadCloser
+	s    *Storage
+	path string
+}
+
+func (r formattedReadCloser) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	if err == nil || err == io.EOF {
+		return n, err
+	}
+	return n, r.s.formatError("read_stream", err, r.path)
+}
+
+func (r formattedReadCloser) Close() error {
+	return r.s.formatError("read_stream", r.rc.Close(), r.path)
+}
@@ -0,0 +1,105 @@
+package azfile
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// SASProvider will be called to get a fresh SAS token, with or without the leading "?".
+//
+// The token will be cached until it's about to expire, which is decided by its `se` parameter.
+type SASProvider func(ctx context.Context) (string, error)
+
+// TokenProvider will be called to get a fresh OAuth token and its expiry time.
+type TokenProvider func(ctx context.Context) (token string, expiresOn time.Time, err error)
+
+const (
+	// sasRefreshBefore is how long before the expiry a SAS token will be refreshed.
+	sasRefreshBefore = 5 * time.Minute
+	// sasRefreshInterval is the interval to refresh a SAS token without expiry.
+	sasRefreshInterval = 5 * time.Minute
+)
+
+// sasQueryKeys are the query parameters of SAS, they will be replaced by the fresh token.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/create-service-sas
+var sasQueryKeys = []string{
+	"sv", "ss", "srt", "sp", "se", "st", "spr", "sip", "si", "sr", "sig",
+	"rscc", "rscd", "rsce", "rscl", "rsct", "skoid", "sktid", "skt", "ske", "sks", "skv",
+}
+
+// sasRefreshPolicy will set the SAS token returned by provider into every request.
+type sasRefreshPolicy struct {
+	provider SASProvider
+
+	mu      sync.Mutex
+	token   url.Values
+	refresh time.Time
+}
+
+// Do implements policy.Policy
+func (p *sasRefreshPolicy) Do(req *policy.Request) (*http.Response, error) {
+	token, err := p.getToken(req.Raw().Context())
+	if err != nil {
+		return nil, err
+	}
+
+	raw := req.Raw()
+	q := raw.URL.Query()
+	for _, k := range sasQueryKeys {
+		q.Del(k)
+	}
+	for k, v := range token {
+		q[k] = v
+	}
+	raw.URL.RawQuery = q.Encode()
+
+	return req.Next()
+}
+
+func (p *sasRefreshPolicy) getToken(ctx context.Context) (url.Values, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != nil && time.Now().Before(p.refresh) {
+		return p.token, nil
+	}
+
+	sas, err := p.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := url.ParseQuery(strings.TrimPrefix(sas, "?"))
+	if err != nil {
+		return nil, err
+	}
+
+	p.refresh = time.Now().Add(sasRefreshInterval)
+	if se, err := time.Parse(time.RFC3339, token.Get("se")); err == nil {
+		p.refresh = se.Add(-sasRefreshBefore)
+	}
+	p.token = token
+	return token, nil
+}
+
+// tokenProviderCredential adapts TokenProvider to TokenCredential, the token will
+// be cached and refreshed by the bearer token policy of SDK.
+type tokenProviderCredential struct {
+	provider TokenProvider
+}
+
+// GetToken implements TokenCredential
+func (c tokenProviderCredential) GetToken(ctx context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	token, expiresOn, err := c.provider(ctx)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	return azcore.AccessToken{Token: token, ExpiresOn: expiresOn}, nil
+}
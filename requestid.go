@@ -0,0 +1,39 @@
+package azfile
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// headerClientRequestID is the header carrying the request id supplied by client,
+// which will be recorded in the storage analytics logs.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/storage-analytics-log-format
+const headerClientRequestID = "x-ms-client-request-id"
+
+type clientRequestIDKey struct{}
+
+// withClientRequestID will carry the client request id in ctx, so that all
+// requests sent by the operation will carry it.
+func withClientRequestID(ctx context.Context, has bool, id string) context.Context {
+	if !has || id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clientRequestIDKey{}, id)
+}
+
+// clientRequestIDPolicy will set the client request id carried by request context.
+type clientRequestIDPolicy struct{}
+
+// Do implements policy.Policy
+func (clientRequestIDPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	if id, ok := raw.Context().Value(clientRequestIDKey{}).(string); ok {
+		raw.Header.Set(headerClientRequestID, id)
+	}
+
+	return req.Next()
+}
@@ -0,0 +1,92 @@
+package azfile
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/share"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	. "github.com/beyondstorage/go-storage/v4/types"
+)
+
+func (s *Service) create(ctx context.Context, name string, opt pairServiceCreate) (store Storager, err error) {
+	options := &share.CreateOptions{}
+	// The service default quota will be used if quota is not set.
+	if opt.HasShareQuota {
+		options.Quota = &opt.ShareQuota
+	}
+	if opt.HasShareAccessTier {
+		options.AccessTier = (*share.AccessTier)(&opt.ShareAccessTier)
+	}
+	if opt.HasShareProtocols {
+		options.EnabledProtocols = &opt.ShareProtocols
+	}
+	if opt.HasShareRootSquash {
+		// Root squash is only available for NFS shares, service will reject it for SMB shares.
+		options.RootSquash = (*share.RootSquash)(&opt.ShareRootSquash)
+	}
+
+	_, err = s.service.NewShareClient(name).Create(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newStorage(ps.WithName(name))
+}
+
+func (s *Service) delete(ctx context.Context, name string, opt pairServiceDelete) (err error) {
+	_, err = s.service.NewShareClient(name).Delete(ctx, nil)
+	return err
+}
+
+func (s *Service) get(ctx context.Context, name string, opt pairServiceGet) (store Storager, err error) {
+	return s.newStorage(ps.WithName(name))
+}
+
+func (s *Service) list(ctx context.Context, opt pairServiceList) (sti *StoragerIterator, err error) {
+	input := &storagePageStatus{
+		maxResults: 200,
+	}
+
+	if opt.HasSharePrefix {
+		input.prefix = opt.SharePrefix
+	}
+
+	return NewStoragerIterator(ctx, s.nextStoragePage, input), nil
+}
+
+func (s *Service) nextStoragePage(ctx context.Context, page *StoragerPage) error {
+	input := page.Status.(*storagePageStatus)
+
+	options := &service.ListSharesOptions{
+		Marker:     input.marker,
+		MaxResults: &input.maxResults,
+	}
+	if input.prefix != "" {
+		options.Prefix = &input.prefix
+	}
+
+	// Pager will be created for every page, the marker is kept in page status.
+	output, err := s.service.NewListSharesPager(options).NextPage(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range output.Shares {
+		store, err := s.newStorage(ps.WithName(*v.Name))
+		if err != nil {
+			return err
+		}
+
+		page.Data = append(page.Data, store)
+	}
+
+	if output.NextMarker == nil || *output.NextMarker == "" {
+		return IterateDone
+	}
+
+	input.marker = output.NextMarker
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// ShardedStorage is a Storager which spreads files across multiple shares by the hash
+// of their paths, so that the IOPS and capacity are not limited by a single share.
+//
+// Every file is stored in exactly one share, while directories are created in every
+// share which has files under them. Shares are chosen by rendezvous hashing, so adding
+// a share only moves the files which will be stored in it, but files written before
+// are not moved by the storager and could not be found until moved by the caller.
+type ShardedStorage struct {
+	shards []*Storage
+
+	types.UnimplementedStorager
+}
+
+// NewShardedStorager will create a storager spreading files across the shares of names,
+// which should be created already.
+//
+// The pairs are the same as NewStorager except the name, and applied to every share.
+func NewShardedStorager(names []string, pairs ...types.Pair) (*ShardedStorage, error) {
+	if len(names) == 0 {
+		return nil, services.InitError{Op: "new_storager", Type: Type, Err: fmt.Errorf("no share names"), Pairs: pairs}
+	}
+
+	srv, err := newServicer(pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ShardedStorage{}
+	for _, name := range names {
+		// The name pair is parsed first, so that it's not overwritten by the pairs.
+		store, err := srv.newStorage(append([]types.Pair{ps.WithName(name)}, pairs...)...)
+		if err != nil {
+			return nil, services.InitError{Op: "new_storager", Type: Type, Err: formatError(err), Pairs: pairs}
+		}
+		s.shards = append(s.shards, store)
+	}
+	return s, nil
+}
+
+// String implements Storager.String
+func (s *ShardedStorage) String() string {
+	return fmt.Sprintf("Storager azfile {Names: %s, WorkDir: %s}", strings.Join(s.names(), ","), s.shards[0].workDir)
+}
+
+// Shards will return the storages of all shares in the order of names.
+func (s *ShardedStorage) Shards() []*Storage {
+	return s.shards
+}
+
+// Shard will return the storage of the share which path is stored in.
+func (s *ShardedStorage) Shard(path string) *Storage {
+	var shard *Storage
+	var max uint64
+	for _, v := range s.shards {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(v.name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(path))
+		if w := h.Sum64(); shard == nil || w > max {
+			shard, max = v, w
+		}
+	}
+	return shard
+}
+
+func (s *ShardedStorage) names() []string {
+	names := make([]string, 0, len(s.shards))
+	for _, v := range s.shards {
+		names = append(names, v.name)
+	}
+	return names
+}
+
+// Create implements Storager.Create
+func (s *ShardedStorage) Create(path string, pairs ...types.Pair) (o *types.Object) {
+	return s.Shard(path).Create(path, pairs...)
+}
+
+// Delete implements Storager.Delete
+func (s *ShardedStorage) Delete(path string, pairs ...types.Pair) (err error) {
+	return s.DeleteWithContext(context.Background(), path, pairs...)
+}
+
+// DeleteWithContext implements Storager.DeleteWithContext
+//
+// Directories are deleted from every share. Without object mode, the path is deleted
+// from its share as a file or directory, and then as a directory from other shares.
+func (s *ShardedStorage) DeleteWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	isDir, known := shardedObjectMode(path, pairs)
+	if known && !isDir {
+		return s.Shard(path).DeleteWithContext(ctx, path, pairs...)
+	}
+
+	shard := s.Shard(path)
+	if !known {
+		err = shard.DeleteWithContext(ctx, path, pairs...)
+		if err != nil {
+			return err
+		}
+	}
+	for _, v := range s.shards {
+		if !known && v == shard {
+			continue
+		}
+		// Delete is idempotent, so the shares without the directory are fine.
+		err = v.DeleteWithContext(ctx, path, withDirMode(pairs)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List implements Storager.List
+func (s *ShardedStorage) List(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	return s.ListWithContext(context.Background(), path, pairs...)
+}
+
+// ListWithContext implements Storager.ListWithContext
+//
+// The objects of all shares are listed one share after another, and the directories
+// listed by more than one share are returned only once. The merged iterator could not
+// be continued, so continuation_token is not supported.
+func (s *ShardedStorage) ListWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	input := &shardedPageStatus{
+		dirs: make(map[string]bool),
+	}
+	for _, v := range s.shards {
+		it, err := v.ListWithContext(ctx, path, pairs...)
+		if err != nil {
+			return nil, err
+		}
+		input.iterators = append(input.iterators, it)
+	}
+	return types.NewObjectIterator(ctx, nextShardedObjectPage, input), nil
+}
+
+// Metadata implements Storager.Metadata
+//
+// The system metadata of shares are not merged, get them from Shards instead.
+func (s *ShardedStorage) Metadata(pairs ...types.Pair) (meta *types.StorageMeta) {
+	meta = types.NewStorageMeta()
+	meta.Name = strings.Join(s.names(), ",")
+	meta.WorkDir = s.shards[0].workDir
+	return meta
+}
+
+// Read implements Storager.Read
+func (s *ShardedStorage) Read(path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return s.ReadWithContext(context.Background(), path, w, pairs...)
+}
+
+// ReadWithContext implements Storager.ReadWithContext
+func (s *ShardedStorage) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return s.Shard(path).ReadWithContext(ctx, path, w, pairs...)
+}
+
+// Stat implements Storager.Stat
+func (s *ShardedStorage) Stat(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.StatWithContext(context.Background(), path, pairs...)
+}
+
+// StatWithContext implements Storager.StatWithContext
+//
+// Directories are found in any share which has files under them. Without object mode,
+// the path is detected in its share first, and then as a directory in other shares.
+func (s *ShardedStorage) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	isDir, known := shardedObjectMode(path, pairs)
+	if known && !isDir {
+		return s.Shard(path).StatWithContext(ctx, path, pairs...)
+	}
+
+	shard := s.Shard(path)
+	if !known {
+		o, err = shard.StatWithContext(ctx, path, pairs...)
+		if err == nil || !errors.Is(err, services.ErrObjectNotExist) {
+			return o, err
+		}
+	}
+	for _, v := range s.shards {
+		if !known && v == shard {
+			continue
+		}
+		o, err = v.StatWithContext(ctx, path, withDirMode(pairs)...)
+		if err == nil || !errors.Is(err, services.ErrObjectNotExist) {
+			return o, err
+		}
+	}
+	return nil, err
+}
+
+// Write implements Storager.Write
+func (s *ShardedStorage) Write(path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteWithContext(context.Background(), path, r, size, pairs...)
+}
+
+// WriteWithContext implements Storager.WriteWithContext
+func (s *ShardedStorage) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.Shard(path).WriteWithContext(ctx, path, r, size, pairs...)
+}
+
+// shardedObjectMode will check whether path refers to a directory like isDirPath, the
+// first object_mode pair takes precedence like the parsed pairs. known is false if
+// the mode is detected by the storage.
+func shardedObjectMode(path string, pairs []types.Pair) (isDir, known bool) {
+	for _, v := range pairs {
+		if v.Key == "object_mode" {
+			return v.Value.(types.ObjectMode).IsDir(), true
+		}
+	}
+	if strings.HasSuffix(path, "/") {
+		return true, true
+	}
+	return false, false
+}
+
+// withDirMode will return pairs with the directory object mode taking precedence.
+func withDirMode(pairs []types.Pair) []types.Pair {
+	return append([]types.Pair{ps.WithObjectMode(types.ModeDir)}, pairs...)
+}
+
+// shardedPageStatus is the status of listing all shares one after another.
+type shardedPageStatus struct {
+	iterators []*types.ObjectIterator
+	index     int
+	// dirs are the directories which have been listed.
+	dirs map[string]bool
+}
+
+// ContinuationToken implements Continuable.ContinuationToken
+func (i *shardedPageStatus) ContinuationToken() string {
+	return ""
+}
+
+func nextShardedObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*shardedPageStatus)
+
+	for len(page.Data) < defaultListPageSize {
+		if input.index == len(input.iterators) {
+			if len(page.Data) > 0 {
+				return nil
+			}
+			return types.IterateDone
+		}
+
+		o, err := input.iterators[input.index].Next()
+		if errors.Is(err, types.IterateDone) {
+			input.index++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if o.Mode.IsDir() {
+			if input.dirs[o.Path] {
+				continue
+			}
+			input.dirs[o.Path] = true
+		}
+		page.Data = append(page.Data, o)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/share"
+)
+
+// Available access tiers of the share.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/files/storage-files-planning#storage-tiers
+const (
+	AccessTierTransactionOptimized = string(share.AccessTierTransactionOptimized)
+	AccessTierHot                  = string(share.AccessTierHot)
+	AccessTierCool                 = string(share.AccessTierCool)
+	AccessTierPremium              = string(share.AccessTierPremium)
+)
+
+// Available protocols of the share, NFS shares are only available in premium FileStorage accounts.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/create-share
+const (
+	ShareProtocolSMB = "SMB"
+	ShareProtocolNFS = "NFS"
+)
+
+// Available root squash settings of the NFS share.
+const (
+	RootSquashNoRootSquash = string(share.RootSquashNoRootSquash)
+	RootSquashRootSquash   = string(share.RootSquashRootSquash)
+	RootSquashAllSquash    = string(share.RootSquashAllSquash)
+)
+
+// SetQuota will set the quota of the share in GiB.
+//
+// This function will create a context by default.
+func (s *Storage) SetQuota(quota int32) (err error) {
+	return s.SetQuotaWithContext(context.Background(), quota)
+}
+
+// SetQuotaWithContext will set the quota of the share in GiB.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-share-properties
+func (s *Storage) SetQuotaWithContext(ctx context.Context, quota int32) (err error) {
+	defer func() {
+		err = s.formatError("set_quota", err)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err = s.share.SetProperties(ctx, &share.SetPropertiesOptions{
+		Quota: &quota,
+	})
+	return err
+}
+
+// SetAccessTier will set the access tier of the share.
+//
+// This function will create a context by default.
+func (s *Storage) SetAccessTier(tier string) (err error) {
+	return s.SetAccessTierWithContext(context.Background(), tier)
+}
+
+// SetAccessTierWithContext will set the access tier of the share.
+//
+// Premium is only available for shares in FileStorage accounts, and the tier of them could not be changed.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-share-properties
+func (s *Storage) SetAccessTierWithContext(ctx context.Context, tier string) (err error) {
+	defer func() {
+		err = s.formatError("set_access_tier", err)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	accessTier := share.AccessTier(tier)
+	_, err = s.share.SetProperties(ctx, &share.SetPropertiesOptions{
+		AccessTier: &accessTier,
+	})
+	return err
+}
+
+// GetShareMetadata will get the metadata of the share.
+//
+// This function will create a context by default.
+func (s *Storage) GetShareMetadata() (metadata map[string]string, err error) {
+	return s.GetShareMetadataWithContext(context.Background())
+}
+
+// GetShareMetadataWithContext will get the metadata of the share, the keys are in lower case.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/get-share-metadata
+func (s *Storage) GetShareMetadataWithContext(ctx context.Context) (metadata map[string]string, err error) {
+	defer func() {
+		err = s.formatError("get_share_metadata", err)
+	}()
+
+	output, err := s.share.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseMetadata(output.Metadata), nil
+}
+
+// SetShareMetadata will replace the metadata of the share.
+//
+// This function will create a context by default.
+func (s *Storage) SetShareMetadata(metadata map[string]string) (err error) {
+	return s.SetShareMetadataWithContext(context.Background(), metadata)
+}
+
+// SetShareMetadataWithContext will replace the metadata of the share, all existing
+// metadata will be removed if metadata is empty.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-share-metadata
+func (s *Storage) SetShareMetadataWithContext(ctx context.Context, metadata map[string]string) (err error) {
+	defer func() {
+		err = s.formatError("set_share_metadata", err)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err = s.share.SetMetadata(ctx, &share.SetMetadataOptions{
+		Metadata: formatMetadata(metadata),
+	})
+	return err
+}
+
+// ShareStatistics is the usage of the share.
+type ShareStatistics struct {
+	// UsageBytes is the approximate size of the data stored in the share.
+	UsageBytes int64
+	// Date is the time when the statistics were returned by service.
+	Date time.Time
+}
+
+// Statistics will get the usage of the share.
+//
+// This function will create a context by default.
+func (s *Storage) Statistics() (stats ShareStatistics, err error) {
+	return s.StatisticsWithContext(context.Background())
+}
+
+// StatisticsWithContext will get the usage of the share, which only costs a request
+// without the properties of the share.
+//
+// The usage is updated asynchronously by service, so it could lag behind the writes.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/get-share-stats
+func (s *Storage) StatisticsWithContext(ctx context.Context) (stats ShareStatistics, err error) {
+	defer func() {
+		err = s.formatError("statistics", err)
+	}()
+
+	output, err := s.share.GetStatistics(ctx, nil)
+	if err != nil {
+		return ShareStatistics{}, err
+	}
+	if output.ShareUsageBytes != nil {
+		stats.UsageBytes = *output.ShareUsageBytes
+	}
+	if output.Date != nil {
+		stats.Date = *output.Date
+	}
+	return stats, nil
+}
+
+// gib is the unit of share quota.
+const gib = 1 << 30
+
+// checkQuota will return ErrShareQuotaExceeded if the size to write exceeds the free space of share.
+//
+// The usage returned by service is updated asynchronously, so this check is best effort
+// and the service will still reject the writes beyond quota.
+func (s *Storage) checkQuota(ctx context.Context, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	props, err := s.share.GetProperties(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if props.Quota == nil {
+		return nil
+	}
+
+	stats, err := s.share.GetStatistics(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var usage int64
+	if stats.ShareUsageBytes != nil {
+		usage = *stats.ShareUsageBytes
+	}
+
+	if free := int64(*props.Quota)*gib - usage; size > free {
+		return fmt.Errorf("%w: %d bytes to write, %d bytes free", ErrShareQuotaExceeded, size, free)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/sas"
+)
+
+// QuerySignHTTPDelete will return a signed Delete File request.
+//
+// This function will create a context by default.
+func (s *Storage) QuerySignHTTPDelete(path string, expire time.Duration) (req *http.Request, err error) {
+	return s.QuerySignHTTPDeleteWithContext(context.Background(), path, expire)
+}
+
+// QuerySignHTTPDeleteWithContext will return a signed Delete File request, the SAS
+// only grants the delete permission of the file.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/delete-file2
+func (s *Storage) QuerySignHTTPDeleteWithContext(ctx context.Context, path string, expire time.Duration) (req *http.Request, err error) {
+	defer func() {
+		err = s.formatError("query_sign_http_delete", err, path)
+	}()
+
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	u, err := s.signFileURL(path, expire, sas.FilePermissions{Delete: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return http.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+}
+
+// QuerySignHTTPList will return a signed List Directories and Files request.
+//
+// This function will create a context by default.
+func (s *Storage) QuerySignHTTPList(path string, expire time.Duration) (req *http.Request, err error) {
+	return s.QuerySignHTTPListWithContext(context.Background(), path, expire)
+}
+
+// QuerySignHTTPListWithContext will return a signed List Directories and Files request
+// of the directory, parameters like prefix and marker could be added to the query.
+//
+// A file SAS could not list directories, so the SAS is signed for the share but only
+// grants the list permission, which allows listing every directory of the share.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/list-directories-and-files
+func (s *Storage) QuerySignHTTPListWithContext(ctx context.Context, path string, expire time.Duration) (req *http.Request, err error) {
+	defer func() {
+		err = s.formatError("query_sign_http_list", err, path)
+	}()
+
+	su, err := s.share.GetSASURL(sas.SharePermissions{List: true}, time.Now().UTC().Add(expire), nil)
+	if errors.Is(err, fileerror.MissingSharedKeyCredential) {
+		return nil, ErrSharedKeyRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := url.Parse(su)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(s.dirClient(path).URL())
+	if err != nil {
+		return nil, err
+	}
+	// The URL of directory could carry the share snapshot.
+	query := u.Query()
+	for k, v := range parsed.Query() {
+		query[k] = v
+	}
+	query.Set("restype", "directory")
+	query.Set("comp", "list")
+	u.RawQuery = query.Encode()
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+}
@@ -0,0 +1,196 @@
+package azfile
+
+import (
+	"context"
+	pathpkg "path"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+)
+
+// FileVersion is the file in a snapshot of the share.
+type FileVersion struct {
+	// Snapshot is the timestamp of the share snapshot, which is used to restore the version.
+	Snapshot string
+
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// CreateSnapshot will create a snapshot of the share and return the snapshot timestamp.
+//
+// This function will create a context by default.
+func (s *Storage) CreateSnapshot() (snapshot string, err error) {
+	return s.CreateSnapshotWithContext(context.Background())
+}
+
+// CreateSnapshotWithContext will create a snapshot of the share and return the snapshot timestamp.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/snapshot-share
+func (s *Storage) CreateSnapshotWithContext(ctx context.Context) (snapshot string, err error) {
+	defer func() {
+		err = s.formatError("create_snapshot", err)
+	}()
+
+	if s.readOnly {
+		return "", ErrReadOnly
+	}
+
+	output, err := s.share.CreateSnapshot(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return *output.Snapshot, nil
+}
+
+// DeleteSnapshot will delete the specified snapshot of the share.
+//
+// This function will create a context by default.
+func (s *Storage) DeleteSnapshot(snapshot string) (err error) {
+	return s.DeleteSnapshotWithContext(context.Background(), snapshot)
+}
+
+// DeleteSnapshotWithContext will delete the specified snapshot of the share.
+//
+// Like Delete, DeleteSnapshot is idempotent.
+func (s *Storage) DeleteSnapshotWithContext(ctx context.Context, snapshot string) (err error) {
+	defer func() {
+		err = s.formatError("delete_snapshot", err)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	client, err := s.share.WithSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Delete(ctx, nil)
+	if err != nil && !checkError(err, fileNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// ListVersions will list the versions of the file in all snapshots of the share.
+//
+// This function will create a context by default.
+func (s *Storage) ListVersions(path string) (versions []FileVersion, err error) {
+	return s.ListVersionsWithContext(context.Background(), path)
+}
+
+// ListVersionsWithContext will list the versions of the file in all snapshots of the share,
+// from the oldest to the newest.
+//
+// Snapshots in which the file doesn't exist are skipped, and the live file is not included.
+func (s *Storage) ListVersionsWithContext(ctx context.Context, path string) (versions []FileVersion, err error) {
+	defer func() {
+		err = s.formatError("list_versions", err, path)
+	}()
+
+	snapshots, err := s.listSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		client, err := s.snapshotFileClient(snapshot, path)
+		if err != nil {
+			return nil, err
+		}
+
+		output, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			if checkError(err, fileNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		version := FileVersion{Snapshot: snapshot}
+		if output.ContentLength != nil {
+			version.Size = *output.ContentLength
+		}
+		if output.ETag != nil {
+			version.ETag = string(*output.ETag)
+		}
+		if output.LastModified != nil {
+			version.LastModified = *output.LastModified
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// RestoreVersion will restore the file to the version in the snapshot.
+//
+// This function will create a context by default.
+func (s *Storage) RestoreVersion(path string, snapshot string) (err error) {
+	return s.RestoreVersionWithContext(context.Background(), path, snapshot)
+}
+
+// RestoreVersionWithContext will restore the file to the version in the snapshot by
+// server-side copy, the SMB attributes, timestamps and permission are restored too.
+func (s *Storage) RestoreVersionWithContext(ctx context.Context, path string, snapshot string) (err error) {
+	defer func() {
+		err = s.formatError("restore_version", err, path, snapshot)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	client, err := s.snapshotFileClient(snapshot, path)
+	if err != nil {
+		return err
+	}
+
+	options, err := s.formatCopyOptions(ctx, true, false, "", false, "", false, "")
+	if err != nil {
+		return err
+	}
+
+	return s.startCopy(ctx, client.URL(), path, options, nil)
+}
+
+// listSnapshots will list the timestamps of all snapshots of the share.
+func (s *Storage) listSnapshots(ctx context.Context) (snapshots []string, err error) {
+	pager := s.service.NewListSharesPager(&service.ListSharesOptions{
+		Include: service.ListSharesInclude{Snapshots: true},
+		Prefix:  &s.name,
+	})
+	for pager.More() {
+		output, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.Shares {
+			// Shares whose name begin with our name are listed too.
+			if v.Name == nil || *v.Name != s.name || v.Snapshot == nil {
+				continue
+			}
+			snapshots = append(snapshots, *v.Snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// snapshotFileClient will return the client of the file in the snapshot.
+func (s *Storage) snapshotFileClient(snapshot string, path string) (*file.Client, error) {
+	client, err := s.service.NewShareClient(s.name).WithSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, name := pathpkg.Split(s.getAbsFilePath(path))
+	return subdirectoryClient(client.NewRootDirectoryClient(), dir).NewFileClient(name), nil
+}
@@ -0,0 +1,100 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// StatResult is the result of a path stated by StatMulti.
+type StatResult struct {
+	// Object is nil if Err is not nil.
+	Object *types.Object
+	// Err wraps services.ErrObjectNotExist if the path doesn't exist.
+	Err error
+}
+
+// StatMulti will stat all paths with at most concurrency stats at the same time.
+//
+// This function will create a context by default.
+func (s *Storage) StatMulti(paths []string, concurrency int, pairs ...types.Pair) (results map[string]StatResult, err error) {
+	return s.StatMultiWithContext(context.Background(), paths, concurrency, pairs...)
+}
+
+// StatMultiWithContext will stat all paths with at most concurrency stats at the same time.
+//
+// The pairs of Stat are supported and applied to every path. The result of every path
+// is returned in results keyed by the path, a failed path doesn't stop the others.
+// err is only returned if the pairs are invalid.
+func (s *Storage) StatMultiWithContext(ctx context.Context, paths []string, concurrency int, pairs ...types.Pair) (results map[string]StatResult, err error) {
+	defer func() {
+		err = s.formatError("stat_multi", err)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.Stat...)
+	opt, err := s.parsePairStorageStat(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	results = make(map[string]StatResult, len(paths))
+	set := func(path string, o *types.Object, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[path] = StatResult{Object: o, Err: s.formatError("stat", err, path)}
+	}
+
+	// Tasks never fail, so that the pool will not be canceled by a failed path.
+	pool, ctx := newWorkerPool(ctx, concurrency)
+	for i, path := range paths {
+		path := path
+		ok := pool.Go(ctx, func() error {
+			o, err := s.stat(ctx, path, opt)
+			set(path, o, err)
+			return nil
+		})
+		if !ok {
+			// The context has been canceled, the rest paths are not stated.
+			for _, v := range paths[i:] {
+				set(v, nil, ctx.Err())
+			}
+			break
+		}
+	}
+	_ = pool.Wait()
+
+	return results, nil
+}
+
+// ExistsMulti will check whether the paths exist with at most concurrency stats at the same time.
+//
+// This function will create a context by default.
+func (s *Storage) ExistsMulti(paths []string, concurrency int, pairs ...types.Pair) (exists map[string]bool, err error) {
+	return s.ExistsMultiWithContext(context.Background(), paths, concurrency, pairs...)
+}
+
+// ExistsMultiWithContext will check whether the paths exist with at most concurrency stats
+// at the same time.
+//
+// Unlike StatMulti, the first error other than not found will be returned, since the
+// existence of that path is unknown.
+func (s *Storage) ExistsMultiWithContext(ctx context.Context, paths []string, concurrency int, pairs ...types.Pair) (exists map[string]bool, err error) {
+	results, err := s.StatMultiWithContext(ctx, paths, concurrency, pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	exists = make(map[string]bool, len(results))
+	for _, path := range paths {
+		r := results[path]
+		if r.Err != nil && !errors.Is(r.Err, services.ErrObjectNotExist) {
+			return nil, r.Err
+		}
+		exists[path] = r.Err == nil
+	}
+	return exists, nil
+}
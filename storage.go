@@ -2,16 +2,139 @@ package azfile
 
 import (
 	"context"
+	"crypto/md5"
 	"encoding/base64"
+	"fmt"
+	"hash"
 	"io"
+	"net/http"
+	"net/url"
+	pathpkg "path"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/Azure/azure-storage-file-go/azfile"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/sas"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/beyondstorage/go-storage/v4/pkg/iowrap"
+	"github.com/beyondstorage/go-storage/v4/services"
 	. "github.com/beyondstorage/go-storage/v4/types"
 )
 
+func (s *Storage) commitAppend(ctx context.Context, o *Object, opt pairStorageCommitAppend) (err error) {
+	defer s.statCache.invalidate(o.ID)
+
+	// Every range has been uploaded in WriteAppend, so there is nothing to commit.
+	return nil
+}
+
+func (s *Storage) completeMultipart(ctx context.Context, o *Object, parts []*Part, opt pairStorageCompleteMultipart) (err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+
+	defer s.statCache.invalidate(o.ID)
+
+	size, ok := o.GetContentLength()
+	if !ok {
+		err = fmt.Errorf("content length is not set")
+		return
+	}
+
+	// All ranges have been uploaded in WriteMultipart, we only need to make sure
+	// there is no hole left in the file.
+	var total int64
+	for _, v := range parts {
+		total += v.Size
+	}
+	if total != size {
+		err = fmt.Errorf("parts size %d mismatch with content length %d", total, size)
+		return
+	}
+
+	o.Mode.Del(ModePart)
+	o.Mode.Add(ModeRead | ModeAppend)
+	o.SetAppendOffset(size)
+
+	return nil
+}
+
+func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStorageCopy) (err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(dst)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(dst))
+
+	ctx, span := s.startSpan(ctx, "copy", src)
+	span.SetAttributes(attribute.String("azfile.dst", s.getAbsPath(dst)))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	options, err := s.formatCopyOptions(ctx, opt.HasCopySMBInfo && opt.CopySMBInfo,
+		opt.HasFileAttributes, opt.FileAttributes,
+		opt.HasFilePermission, opt.FilePermission,
+		opt.HasFilePermissionKey, opt.FilePermissionKey)
+	if err != nil {
+		return err
+	}
+
+	srcClient := s.fileClient(src)
+	if opt.HasSourceShare {
+		// The source in another share of the same account is authorized by our
+		// credential, so no SAS is needed.
+		srcClient = s.sourceFileClient(opt.SourceShare, src)
+	}
+	source := srcClient.URL()
+
+	// The source could still be read by the copy while leased, but not be written by others.
+	if opt.HasLeaseFiles && opt.LeaseFiles {
+		_, unlock, err := lockFile(ctx, srcClient)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	var copyProgress CopyProgressFunc
+	if opt.HasCopyProgress {
+		copyProgress = opt.CopyProgress
+	}
+	var progress ProgressFunc
+	if opt.HasProgress {
+		progress = opt.Progress
+	}
+
+	err = s.startCopy(ctx, source, dst, options, withCopyProgress(copyProgress, progress))
+	if err != nil {
+		return err
+	}
+	if opt.HasVerifyTransfer && opt.VerifyTransfer {
+		err = s.verifyCopy(ctx, srcClient, dst)
+		if err != nil {
+			return err
+		}
+	}
+	if progress == nil {
+		return nil
+	}
+	return s.reportCopied(ctx, dst, progress)
+}
+
 func (s *Storage) create(path string, opt pairStorageCreate) (o *Object) {
 	rp := s.getAbsPath(path)
 
@@ -29,26 +152,157 @@ func (s *Storage) create(path string, opt pairStorageCreate) (o *Object) {
 	return o
 }
 
+func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorageCreateAppend) (o *Object, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	rp := s.getAbsPath(path)
+
+	// The size of encrypted file must be known before the content is written.
+	if s.encryptionKey != nil {
+		return nil, fmt.Errorf("%w: client-side encryption", services.ErrCapabilityInsufficient)
+	}
+	// The gzip stream could not be appended, see writeAppend.
+	if s.compression != "" {
+		return nil, fmt.Errorf("%w: compression", services.ErrCapabilityInsufficient)
+	}
+
+	headers := &file.HTTPHeaders{}
+
+	if opt.HasCacheControl {
+		headers.CacheControl = &opt.CacheControl
+	}
+	if opt.HasContentDisposition {
+		headers.ContentDisposition = &opt.ContentDisposition
+	}
+	if opt.HasContentEncoding {
+		headers.ContentEncoding = &opt.ContentEncoding
+	}
+	if opt.HasContentLanguage {
+		headers.ContentLanguage = &opt.ContentLanguage
+	}
+	if opt.HasContentType {
+		headers.ContentType = &opt.ContentType
+	}
+
+	// Create an empty file, it will be resized while appending.
+	// `Create` will overwrite the file if it exists.
+	err = s.withParentDirs(ctx, path, func() error {
+		_, err := s.fileClient(path).Create(ctx, 0, &file.CreateOptions{
+			HTTPHeaders: headers,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o = s.newObject(true)
+	o.ID = rp
+	o.Path = path
+	o.Mode |= ModeRead | ModeAppend
+	o.SetAppendOffset(0)
+
+	return o, nil
+}
+
 func (s *Storage) createDir(ctx context.Context, path string, opt pairStorageCreateDir) (o *Object, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
 	rp := s.getAbsPath(path)
 
-	attribute := azfile.FileAttributeNone
+	properties := &file.SMBProperties{}
+	if opt.HasFileAttributes {
+		properties.Attributes, err = file.ParseNTFSFileAttributes(&opt.FileAttributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.HasFileCreationTime {
+		properties.CreationTime = &opt.FileCreationTime
+	}
+	if opt.HasFileLastWriteTime {
+		properties.LastWriteTime = &opt.FileLastWriteTime
+	}
+
+	permissions, err := s.formatFilePermission(ctx, opt.HasFilePermission, opt.FilePermission, opt.HasFilePermissionKey, opt.FilePermissionKey)
+	if err != nil {
+		return nil, err
+	}
 
-	properties := azfile.SMBProperties{
-		FileAttributes: &attribute,
+	var metadata map[string]*string
+	if opt.HasUserMetadata {
+		metadata = formatMetadata(opt.UserMetadata)
 	}
 
-	fi, err := s.client.NewDirectoryURL(path).GetProperties(ctx)
+	dirClient := s.dirClient(path)
+
+	fi, err := dirClient.GetProperties(ctx, nil)
 	if err == nil {
 		// The directory exist, we should set the metadata.
 		o = s.newObject(true)
-		o.SetLastModified(fi.LastModified())
+		if fi.LastModified != nil {
+			o.SetLastModified(*fi.LastModified)
+		}
+
+		if opt.HasUserMetadata {
+			_, err = dirClient.SetMetadata(ctx, &directory.SetMetadataOptions{
+				Metadata: metadata,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if opt.HasFileAttributes || opt.HasFilePermission || opt.HasFilePermissionKey ||
+			opt.HasFileCreationTime || opt.HasFileLastWriteTime {
+			_, err = dirClient.SetProperties(ctx, &directory.SetPropertiesOptions{
+				FileSMBProperties: properties,
+				FilePermissions:   permissions,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else if !checkError(err, fileNotFound) {
 		// Something error other then file not found happened, return directly.
 		return nil, err
 	} else {
 		// The directory not exists, we should create the directory.
-		_, err = s.client.NewDirectoryURL(path).Create(ctx, nil, properties)
+		create := func() error {
+			_, err := dirClient.Create(ctx, &directory.CreateOptions{
+				FileSMBProperties: properties,
+				FilePermissions:   permissions,
+				Metadata:          metadata,
+			})
+			return err
+		}
+
+		// Parent directories are created with default properties like `mkdir -p`.
+		createParents := s.features.VirtualDir || opt.HasCreateParents && opt.CreateParents
+		err = s.withParentDirsIf(ctx, createParents, path, create)
 		if err != nil {
 			return nil, err
 		}
@@ -60,127 +314,817 @@ func (s *Storage) createDir(ctx context.Context, path string, opt pairStorageCre
 	o.Path = path
 	o.Mode |= ModeDir
 
+	if opt.HasUserMetadata {
+		o.SetUserMetadata(opt.UserMetadata)
+	}
+
 	return
 }
 
+// createLink will create a hard link with hard_link, or a virtual link with virtual_link.
+//
+// Native symbolic links of NFS shares could not be created, since the SDK we use
+// doesn't expose Create Symbolic Link.
+func (s *Storage) createLink(ctx context.Context, path string, target string, opt pairStorageCreateLink) (o *Object, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	rp := s.getAbsPath(path)
+
+	if opt.HasHardLink && opt.HardLink {
+		// The target of hard link must be an existing file in the same share.
+		err = s.withParentDirs(ctx, path, func() error {
+			_, err := s.fileClient(path).CreateHardLink(ctx, s.getAbsFilePath(target), nil)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		o = s.newObject(false)
+		o.ID = rp
+		o.Path = path
+		o.Mode |= ModeRead
+		return o, nil
+	}
+
+	if !s.features.VirtualLink {
+		return nil, fmt.Errorf("%w: symbolic link without virtual_link", services.ErrCapabilityInsufficient)
+	}
+
+	// SMB shares don't support symbolic links, so we store the target in an empty file's metadata.
+	err = s.withParentDirs(ctx, path, func() error {
+		_, err := s.fileClient(path).Create(ctx, 0, &file.CreateOptions{
+			Metadata: map[string]*string{
+				metadataLinkTarget: &target,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o = s.newObject(true)
+	o.ID = rp
+	o.Path = path
+	o.Mode |= ModeLink
+	o.SetLinkTarget(target)
+
+	return o, nil
+}
+
+func (s *Storage) createMultipart(ctx context.Context, path string, opt pairStorageCreateMultipart) (o *Object, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	rp := s.getAbsPath(path)
+
+	// The size of encrypted file must be known before the content is written.
+	if s.encryptionKey != nil {
+		return nil, fmt.Errorf("%w: client-side encryption", services.ErrCapabilityInsufficient)
+	}
+
+	partSize := int64(defaultPartSize)
+	if opt.HasPartSize {
+		partSize = opt.PartSize
+	}
+	if partSize <= 0 {
+		err = fmt.Errorf("part size %d is invalid", partSize)
+		return
+	}
+
+	headers := &file.HTTPHeaders{}
+
+	if opt.HasCacheControl {
+		headers.CacheControl = &opt.CacheControl
+	}
+	if opt.HasContentDisposition {
+		headers.ContentDisposition = &opt.ContentDisposition
+	}
+	if opt.HasContentEncoding {
+		headers.ContentEncoding = &opt.ContentEncoding
+	}
+	if opt.HasContentLanguage {
+		headers.ContentLanguage = &opt.ContentLanguage
+	}
+	if opt.HasContentType {
+		headers.ContentType = &opt.ContentType
+	}
+
+	// The whole file is allocated while created, so the quota is checked once for all
+	// parts, and the parts written later don't use more space.
+	if opt.HasCheckQuota && opt.CheckQuota {
+		err = s.checkQuota(ctx, opt.Size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Parts are mapped to ranges of the file, so we need to create the file with its total size first.
+	err = s.withParentDirs(ctx, path, func() error {
+		_, err := s.fileClient(path).Create(ctx, opt.Size, &file.CreateOptions{
+			HTTPHeaders: headers,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o = s.newObject(true)
+	o.ID = rp
+	o.Path = path
+	o.Mode |= ModePart
+	o.SetContentLength(opt.Size)
+	// azfile doesn't have native multipart upload, we use the part size as the multipart id
+	// so that every part could be located by its index.
+	o.SetMultipartID(formatMultipartID(partSize))
+
+	return o, nil
+}
+
 func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete) (err error) {
-	if opt.HasObjectMode && opt.ObjectMode.IsDir() {
-		_, err = s.client.NewDirectoryURL(path).Delete(ctx)
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	ctx, span := s.startSpan(ctx, "delete", path)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+	defer cancel()
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if s.dryRun(DryRunOp{Op: DryRunOpDelete, Path: path}) {
+		return nil
+	}
+
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+		lock:                 opt.HasLeaseFiles && opt.LeaseFiles,
+	}
+
+	// Path ends with "/" could only be a directory, so we don't need to try the file.
+	if isDirPath(path, opt.HasObjectMode, opt.ObjectMode) {
+		_, err = s.dirClient(path).Delete(ctx, nil)
 	} else {
-		_, err = s.client.NewFileURL(path).Delete(ctx)
+		// Conditions only apply to files, directories don't have content to protect.
+		var lease *file.LeaseAccessConditions
+		var unlock func()
+		lease, unlock, err = s.lockConditions(ctx, path, cond, opt.HasLeaseID, opt.LeaseID)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		_, err = s.fileClient(path).Delete(ctx, &file.DeleteOptions{
+			LeaseAccessConditions: lease,
+		})
+
+		// Fall back to directory if object mode is not specified.
+		if err != nil && !opt.HasObjectMode && checkError(err, fileNotFound) {
+			_, err = s.dirClient(path).Delete(ctx, nil)
+		}
+	}
+
+	if err != nil {
+		// azfile Delete is not idempotent, so we need to check file not found error.
+		//
+		// References
+		// - [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
+		// - https://docs.microsoft.com/en-us/rest/api/storageservices/delete-file2#remarks
+		if checkError(err, fileNotFound) {
+			err = nil
+		} else {
+			return err
+		}
+	}
+
+	if s.features.VirtualDir {
+		parent := path
+		if !isDirPath(path, opt.HasObjectMode, opt.ObjectMode) {
+			parent = s.mapPath(path)
+		}
+		err = s.deleteEmptyParentDirs(ctx, parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opt.HasProgress {
+		opt.Progress(Progress{Objects: 1})
+	}
+	return nil
+}
+
+func (s *Storage) fetch(ctx context.Context, path string, src string, opt pairStorageFetch) (err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	options, err := s.formatCopyOptions(ctx, opt.HasCopySMBInfo && opt.CopySMBInfo,
+		opt.HasFileAttributes, opt.FileAttributes,
+		opt.HasFilePermission, opt.FilePermission,
+		opt.HasFilePermissionKey, opt.FilePermissionKey)
+	if err != nil {
+		return err
+	}
+
+	var copyProgress CopyProgressFunc
+	if opt.HasCopyProgress {
+		copyProgress = opt.CopyProgress
+	}
+	var progress ProgressFunc
+	if opt.HasProgress {
+		progress = opt.Progress
+	}
+
+	// The source could be any readable url, like a blob or a file with SAS in
+	// another storage account.
+	err = s.startCopy(ctx, src, path, options, withCopyProgress(copyProgress, progress))
+	if err != nil || progress == nil {
+		return err
+	}
+	return s.reportCopied(ctx, path, progress)
+}
+
+func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *ObjectIterator, err error) {
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	// Only the creation of iterator is traced, pages are fetched lazily.
+	ctx, span := s.startSpan(ctx, "list", path)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	input := &objectPageStatus{
+		maxResults: defaultListPageSize,
+	}
+	if opt.HasListPageSize {
+		if opt.ListPageSize <= 0 || opt.ListPageSize > maxListPageSize {
+			return nil, fmt.Errorf("list page size %d is out of range (0, %d]", opt.ListPageSize, maxListPageSize)
+		}
+		input.maxResults = int32(opt.ListPageSize)
+	}
+	if opt.HasTimeout {
+		input.timeout = opt.Timeout
+	}
+	if opt.HasClientRequestID {
+		// Pages are fetched with the ctx of iterator, so the id is kept in page status.
+		input.clientRequestID = opt.ClientRequestID
+	}
+	if opt.HasCallOptions {
+		input.hasCallOptions = true
+		input.callOptions = opt.CallOptions
+	}
+	if opt.HasListExtendedInfo {
+		input.extendedInfo = opt.ListExtendedInfo
+	}
+	input.filter, err = parseListFilter(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var next NextObjectFunc
+	if !opt.HasListMode || opt.ListMode.IsDir() {
+		input.dir = formatDirPath(path)
+		next = s.nextObjectPageByDir
+	} else if opt.ListMode.IsPrefix() {
+		// azfile only supports filtering by name prefix in one directory, so we list
+		// the parent directory with the name prefix, then walk into its sub directories.
+		if path == "" || strings.HasSuffix(path, "/") {
+			input.dir = path
+		} else {
+			input.dir, input.prefix = formatDirPath(pathpkg.Dir(path)), pathpkg.Base(path)
+		}
+		next = s.nextObjectPageByPrefix
+
+		// Sub directories are listed concurrently, which is much faster for large trees.
+		if c := parseConcurrency(opt.HasConcurrency, opt.Concurrency); c > 1 {
+			if opt.HasContinuationToken {
+				return nil, fmt.Errorf("list with both concurrency and continuation token")
+			}
+			input.concurrency = c
+			next = s.nextObjectPageParallel
+		}
+	} else {
+		return nil, services.ListModeInvalidError{Actual: opt.ListMode}
+	}
+
+	// The token carries the directory being listed, so it takes precedence over the path.
+	if opt.HasContinuationToken {
+		err = input.parseContinuationToken(opt.ContinuationToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.HasProgress {
+		next = withListProgress(next, opt.Progress)
+	}
+	return NewObjectIterator(ctx, next, input), nil
+}
+
+func (s *Storage) listMultipart(ctx context.Context, o *Object, opt pairStorageListMultipart) (pi *PartIterator, err error) {
+	if !o.Mode.IsPart() {
+		err = fmt.Errorf("object is not a part object")
+		return
+	}
+
+	partSize, err := parseMultipartID(o.MustGetMultipartID())
+	if err != nil {
+		return nil, err
+	}
+
+	input := &partPageStatus{
+		partSize: partSize,
+	}
+
+	return NewPartIterator(ctx, s.nextPartPage(o.Path), input), nil
+}
+
+func (s *Storage) metadata(opt pairStorageMetadata) (meta *StorageMeta) {
+	meta = NewStorageMeta()
+	meta.WorkDir = s.workDir
+
+	// Metadata could not return an error, so the quota and usage are filled in the best effort.
+	// It could not be canceled by caller either, so the requests are always sent with timeout.
+	timeout := maxServerTimeout
+	if opt.HasTimeout && opt.Timeout > 0 {
+		timeout = opt.Timeout
+	}
+	ctx, cancel := withTimeout(context.Background(), true, timeout)
+	defer cancel()
+
+	var sm StorageSystemMetadata
+	if output, err := s.share.GetProperties(ctx, nil); err == nil {
+		sm.ShareQuota = deref(output.Quota)
+		// The provisioned performance is only returned for premium shares.
+		sm.ShareProvisionedIOPS = deref(output.ProvisionedIops)
+		sm.ShareProvisionedBandwidthMibps = deref(output.ProvisionedBandwidthMiBps)
+		sm.ShareProvisionedIngressMbps = deref(output.ProvisionedIngressMBps)
+		sm.ShareProvisionedEgressMbps = deref(output.ProvisionedEgressMBps)
+		sm.ShareIncludedBurstIOPS = deref(output.IncludedBurstIops)
+		sm.ShareMaxBurstCreditsForIOPS = deref(output.MaxBurstCreditsForIops)
+	}
+	if output, err := s.share.GetStatistics(ctx, nil); err == nil && output.ShareUsageBytes != nil {
+		sm.ShareUsageBytes = *output.ShareUsageBytes
+	}
+	meta.SetSystemMetadata(sm)
+
+	return meta
+}
+
+func (s *Storage) move(ctx context.Context, src string, dst string, opt pairStorageMove) (err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(dst)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(src))
+	defer s.statCache.invalidate(s.getAbsPath(dst))
+
+	if s.dryRun(DryRunOp{Op: DryRunOpMove, Path: dst, Src: src}) {
+		return nil
+	}
+
+	isDir := opt.HasObjectMode && opt.ObjectMode.IsDir()
+	// The files under the directory are stored at the paths mapped with the directory.
+	if isDir {
+		err = s.checkPathMapper("move dir")
+		if err != nil {
+			return err
+		}
+	}
+
+	// The destination path of rename is relative to the root of the share.
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/rename-file
+	dstPath := s.getAbsFilePath(dst)
+	if isDir {
+		dstPath = s.getAbsPath(dst)
+	}
+
+	return s.withParentDirs(ctx, dst, func() error {
+		var err error
+		if isDir {
+			_, err = s.dirClient(src).Rename(ctx, dstPath, &directory.RenameOptions{
+				ReplaceIfExists: to.Ptr(true),
+			})
+		} else {
+			// Rename is atomic and keeps the metadata and SMB properties of the file.
+			_, err = s.fileClient(src).Rename(ctx, dstPath, &file.RenameOptions{
+				ReplaceIfExists: to.Ptr(true),
+			})
+		}
+		return err
+	})
+}
+
+func (s *Storage) nextObjectPageByDir(ctx context.Context, page *ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	// Iterator doesn't allow an empty page before done, so we keep listing while
+	// all files of the page are filtered out.
+	for len(page.Data) == 0 {
+		output, err := s.nextListPage(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range output.Segment.Directories {
+			o, err := s.formatDirObject(input.dir, v)
+			if err != nil {
+				return err
+			}
+
+			page.Data = append(page.Data, o)
+		}
+
+		for _, v := range output.Segment.Files {
+			o, err := s.formatFileObject(input.dir, v)
+			if err != nil {
+				return err
+			}
+			// The names are matched before stored by the path mapper.
+			if o == nil || !input.filter.match(pathpkg.Base(o.Path), o) {
+				continue
+			}
+
+			page.Data = append(page.Data, o)
+		}
+
+		if output.NextMarker == nil || *output.NextMarker == "" {
+			return IterateDone
+		}
+
+		input.marker = output.NextMarker
+	}
+
+	return nil
+}
+
+func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	// Iterator doesn't allow an empty page before done, so we keep walking until
+	// we got some files or all directories have been listed.
+	for len(page.Data) == 0 {
+		output, err := s.nextListPage(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range output.Segment.Directories {
+			input.dirs = append(input.dirs, input.dir+*v.Name+"/")
+		}
+
+		for _, v := range output.Segment.Files {
+			o, err := s.formatFileObject(input.dir, v)
+			if err != nil {
+				return err
+			}
+			// The names are matched before stored by the path mapper.
+			if o == nil || !input.filter.match(pathpkg.Base(o.Path), o) {
+				continue
+			}
+
+			page.Data = append(page.Data, o)
+		}
+
+		if output.NextMarker != nil && *output.NextMarker != "" {
+			input.marker = output.NextMarker
+			continue
+		}
+
+		// Current directory has been listed, walk into the last pending directory.
+		if len(input.dirs) == 0 {
+			return IterateDone
+		}
+
+		input.dir = input.dirs[len(input.dirs)-1]
+		input.dirs = input.dirs[:len(input.dirs)-1]
+		input.prefix = ""
+		input.marker = nil
+	}
+
+	return nil
+}
+
+func (s *Storage) nextPartPage(path string) NextPartFunc {
+	return func(ctx context.Context, page *PartPage) error {
+		input := page.Status.(*partPageStatus)
+
+		output, err := s.fileClient(path).GetRangeList(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		// Adjacent ranges will be merged by service, so we need to split them by part size.
+		for _, v := range output.Ranges {
+			start, end := *v.Start, *v.End
+			for offset := start; offset <= end; offset += input.partSize {
+				size := input.partSize
+				if offset+size > end+1 {
+					size = end + 1 - offset
+				}
+
+				page.Data = append(page.Data, &Part{
+					Index: int(offset / input.partSize),
+					Size:  size,
+				})
+			}
+		}
+
+		return IterateDone
+	}
+}
+
+// querySignHTTP will return a signed request of read or write, the size pair is required by write.
+func (s *Storage) querySignHTTP(ctx context.Context, op string, path string, expire time.Duration, opt pairStorageQuerySignHTTP) (req *http.Request, err error) {
+	switch op {
+	case OpStoragerRead:
+		return s.querySignHTTPRead(ctx, path, expire)
+	case OpStoragerWrite:
+		if !opt.HasSize {
+			return nil, services.PairRequiredError{Keys: []string{"size"}}
+		}
+		return s.querySignHTTPWrite(ctx, path, opt.Size, expire)
+	default:
+		return nil, NewOperationNotImplementedError(op)
+	}
+}
+
+func (s *Storage) querySignHTTPRead(ctx context.Context, path string, expire time.Duration) (req *http.Request, err error) {
+	u, err := s.signFileURL(path, expire, sas.FilePermissions{Read: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+}
+
+// querySignHTTPWrite will return a signed Put Range request to write the whole file.
+//
+// Put Range could not create the file, and signing doesn't send any request, so the
+// file should be created by caller with at least size bytes before the request sent,
+// like writing an empty file and then Resize it. As the limit of Put Range, the size
+// should not be larger than 4 MiB.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/put-range
+func (s *Storage) querySignHTTPWrite(ctx context.Context, path string, size int64, expire time.Duration) (req *http.Request, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+
+	if size <= 0 || size > maxRangeSize {
+		return nil, fmt.Errorf("size %d is out of the range size limit (0, %d]", size, maxRangeSize)
+	}
+	if err = s.checkPath(path); err != nil {
+		return nil, err
+	}
+
+	su, err := s.signFileURL(path, expire, sas.FilePermissions{Write: true})
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(su)
+	if err != nil {
+		return nil, err
 	}
+	query := u.Query()
+	query.Set("comp", "range")
+	u.RawQuery = query.Encode()
 
+	req, err = http.NewRequestWithContext(ctx, http.MethodPut, u.String(), nil)
 	if err != nil {
-		// azfile Delete is not idempotent, so we need to check file not found error.
-		//
-		// References
-		// - [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
-		// - https://docs.microsoft.com/en-us/rest/api/storageservices/delete-file2#remarks
-		if checkError(err, fileNotFound) {
-			err = nil
-		} else {
-			return err
-		}
+		return nil, err
 	}
 
-	return nil
-}
-
-func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *ObjectIterator, err error) {
-	input := &objectPageStatus{
-		maxResults: 200,
-		prefix:     s.getAbsPath(path),
-	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-write", "update")
+	req.Header.Set("x-ms-range", fmt.Sprintf("bytes=0-%d", size-1))
 
-	return NewObjectIterator(ctx, s.nextObjectPage, input), nil
+	return req, nil
 }
 
-func (s *Storage) metadata(opt pairStorageMetadata) (meta *StorageMeta) {
-	meta = NewStorageMeta()
-	meta.WorkDir = s.workDir
-	return meta
-}
+func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
 
-func (s *Storage) nextObjectPage(ctx context.Context, page *ObjectPage) error {
-	input := page.Status.(*objectPageStatus)
+	ctx, span := s.startSpan(ctx, "read", path)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+	defer cancel()
+	defer func() {
+		span.SetAttributes(attribute.Int64("azfile.size", n))
+		endSpan(span, err)
+	}()
+
+	offset := int64(0)
+	if opt.HasOffset {
+		offset = opt.Offset
+	}
 
-	options := azfile.ListFilesAndDirectoriesOptions{
-		Prefix:     input.prefix,
-		MaxResults: input.maxResults,
+	// A count of 0 means till the end of file.
+	var count int64
+	if opt.HasSize {
+		count = opt.Size
 	}
 
-	output, err := s.client.ListFilesAndDirectoriesSegment(ctx, input.marker, options)
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+	}
+	err = s.checkConditions(ctx, path, cond)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	for _, v := range output.DirectoryItems {
-		o, err := s.formatDirObject(v)
+	w = s.limitWriter(ctx, w)
+
+	// Only the files compressed by us are decompressed, the content encoded by others
+	// is read as is.
+	if s.compression != "" {
+		fi, err := s.fileClient(path).GetProperties(ctx, nil)
 		if err != nil {
-			return err
+			return 0, err
+		}
+		if deref(fi.ContentEncoding) == s.compression {
+			if opt.HasIoCallback {
+				w = iowrap.CallbackWriter(w, opt.IoCallback)
+			}
+			return s.readCompressed(ctx, path, w, offset, count, opt)
 		}
-
-		page.Data = append(page.Data, o)
 	}
 
-	for _, v := range output.FileItems {
-		o, err := s.formatFileObject(v)
+	if s.encryptionKey != nil {
+		client := s.fileClient(path)
+
+		fi, err := client.GetProperties(ctx, nil)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
-		page.Data = append(page.Data, o)
+		// Files which are not encrypted will be read as is.
+		aead, plainSize, ok, err := s.openEncryption(parseMetadata(fi.Metadata))
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			if opt.HasIoCallback {
+				w = iowrap.CallbackWriter(w, opt.IoCallback)
+			}
+			return readEncrypted(ctx, client, w, aead, plainSize, offset, count)
+		}
 	}
 
-	if !output.NextMarker.NotDone() {
-		return IterateDone
-	}
+	concurrency := parseConcurrency(opt.HasConcurrency, opt.Concurrency)
+	verify := opt.HasVerifyContentMd5 && opt.VerifyContentMd5
 
-	input.marker = output.NextMarker
+	// The service only returns the MD5 of ranges no larger than 4 MiB, so we need to
+	// download in ranges while verifying.
+	if concurrency > 1 || verify {
+		client := s.fileClient(path)
 
-	return nil
-}
+		if !opt.HasSize {
+			fi, err := client.GetProperties(ctx, nil)
+			if err != nil {
+				return 0, err
+			}
+			count = *fi.ContentLength - offset
+		}
 
-func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
-	offset := int64(0)
-	if opt.HasOffset {
-		offset = opt.Offset
-	}
+		rangeSize, err := parseChunkSize(opt.HasChunkSize, opt.ChunkSize)
+		if err != nil {
+			return 0, err
+		}
 
-	count := int64(azfile.CountToEnd)
-	if opt.HasSize {
-		count = opt.Size
+		if opt.HasIoCallback {
+			w = iowrap.CallbackWriter(w, opt.IoCallback)
+		}
+
+		return s.downloadRanges(ctx, client, w, offset, count, rangeSize, concurrency, verify, parseReadRetries(opt.HasReadRetries, opt.ReadRetries))
 	}
 
-	output, err := s.client.NewFileURL(path).Download(ctx, offset, count, false)
+	// The stream will be requested again from the last offset if the body is broken.
+	rs, err := s.newReadStream(ctx, path, opt)
 	if err != nil {
 		return 0, err
 	}
 	defer func() {
-		cErr := output.Response().Body.Close()
+		cErr := rs.Close()
 		if cErr != nil {
 			err = cErr
 		}
 	}()
 
-	rc := output.Response().Body
+	var r io.Reader = rs
 	if opt.HasIoCallback {
-		rc = iowrap.CallbackReadCloser(rc, opt.IoCallback)
+		r = iowrap.CallbackReader(r, opt.IoCallback)
 	}
 
-	return io.Copy(w, rc)
+	return io.Copy(w, r)
 }
 
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *Object, err error) {
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	ctx, span := s.startSpan(ctx, "stat", path)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+	defer cancel()
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	rp := s.getAbsPath(path)
 
-	var dirOutput *azfile.DirectoryGetPropertiesResponse
-	var fileOutput *azfile.FileGetPropertiesResponse
+	// The permission is resolved by another request, so it's not cached.
+	cacheable := !opt.HasResolveFilePermission || !opt.ResolveFilePermission
+	var mode ObjectMode
+	if opt.HasObjectMode {
+		mode = opt.ObjectMode
+	}
+	if cacheable {
+		if o, ok := s.statCache.get(rp, mode); ok {
+			return o, nil
+		}
+	}
+
+	var dirOutput directory.GetPropertiesResponse
+	var fileOutput file.GetPropertiesResponse
 
-	if opt.HasObjectMode && opt.ObjectMode.IsDir() {
-		dirOutput, err = s.client.NewDirectoryURL(path).GetProperties(ctx)
+	isDir := isDirPath(path, opt.HasObjectMode, opt.ObjectMode)
+	if isDir {
+		dirOutput, err = s.dirClient(path).GetProperties(ctx, nil)
 	} else {
-		fileOutput, err = s.client.NewFileURL(path).GetProperties(ctx)
+		fileOutput, err = s.fileClient(path).GetProperties(ctx, nil)
+
+		// Fall back to directory if object mode is not specified.
+		if err != nil && !opt.HasObjectMode && checkError(err, fileNotFound) {
+			isDir = true
+			dirOutput, err = s.dirClient(path).GetProperties(ctx, nil)
+		}
 	}
 
 	if err != nil {
@@ -191,79 +1135,543 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	o.ID = rp
 	o.Path = path
 
-	if opt.HasObjectMode && opt.ObjectMode.IsDir() {
+	if isDir {
 		o.Mode |= ModeDir
 
-		o.SetLastModified(dirOutput.LastModified())
-
-		if v := string(dirOutput.ETag()); v != "" {
-			o.SetEtag(v)
+		if v := dirOutput.LastModified; v != nil {
+			o.SetLastModified(*v)
+		}
+		if v := dirOutput.ETag; v != nil {
+			o.SetEtag(string(*v))
+		}
+		if v := dirOutput.Metadata; len(v) > 0 {
+			o.SetUserMetadata(parseUserMetadata(v))
 		}
 
 		var sm ObjectSystemMetadata
-		if v, err := strconv.ParseBool(dirOutput.IsServerEncrypted()); err == nil {
-			sm.ServerEncrypted = v
+		if v := dirOutput.IsServerEncrypted; v != nil {
+			sm.ServerEncrypted = *v
+		}
+		formatSMBProperties(&sm, smbProperties{
+			attributes:    dirOutput.FileAttributes,
+			changeTime:    dirOutput.FileChangeTime,
+			creationTime:  dirOutput.FileCreationTime,
+			id:            dirOutput.ID,
+			lastWriteTime: dirOutput.FileLastWriteTime,
+			parentID:      dirOutput.ParentID,
+			permissionKey: dirOutput.FilePermissionKey,
+		})
+		if opt.HasResolveFilePermission && opt.ResolveFilePermission {
+			sm.FilePermission, err = s.getFilePermission(ctx, sm.FilePermissionKey)
+			if err != nil {
+				return nil, err
+			}
 		}
 		o.SetSystemMetadata(sm)
 	} else {
 		o.Mode |= ModeRead
 
-		o.SetContentLength(fileOutput.ContentLength())
-		o.SetLastModified(fileOutput.LastModified())
+		// Symbolic links only exist in NFS shares, their targets could not be read by
+		// the SDK we use, so the link target is left unset.
+		if v := fileOutput.NFSFileType; v != nil && *v == file.NFSFileTypeSymlink {
+			o.Mode |= ModeLink
+		}
+		if s.features.VirtualLink {
+			// Metadata keys could be canonicalized by the server, use the parsed lower-case keys.
+			if v, ok := parseMetadata(fileOutput.Metadata)[metadataLinkTarget]; ok {
+				o.Mode |= ModeLink
+				o.SetLinkTarget(v)
+			}
+		}
 
-		if v := string(fileOutput.ETag()); v != "" {
-			o.SetEtag(v)
+		if v := fileOutput.ContentLength; v != nil {
+			o.SetContentLength(*v)
+		}
+		// The content length of encrypted file is the size of plaintext.
+		v, encrypted := parseMetadata(fileOutput.Metadata)[metadataEncryptionSize]
+		if encrypted {
+			size, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			o.SetContentLength(size)
+		}
+		// Encrypted files are sealed as a whole, and links should not be written through.
+		if !encrypted && !o.Mode.IsLink() && s.encryptionKey == nil && fileOutput.ContentLength != nil {
+			o.Mode |= ModeAppend
+			o.SetAppendOffset(*fileOutput.ContentLength)
+		}
+		if v := fileOutput.LastModified; v != nil {
+			o.SetLastModified(*v)
+		}
+		if v := fileOutput.ETag; v != nil {
+			o.SetEtag(string(*v))
 		}
-		if v := fileOutput.ContentType(); v != "" {
-			o.SetContentType(v)
+		if v := fileOutput.ContentType; v != nil && *v != "" {
+			o.SetContentType(*v)
 		}
-		if v := fileOutput.ContentMD5(); len(v) > 0 {
+		if v := fileOutput.ContentMD5; len(v) > 0 {
 			o.SetContentMd5(base64.StdEncoding.EncodeToString(v))
 		}
+		if v := fileOutput.Metadata; len(v) > 0 {
+			o.SetUserMetadata(parseUserMetadata(v))
+		}
 
 		var sm ObjectSystemMetadata
-		if v, err := strconv.ParseBool(fileOutput.IsServerEncrypted()); err == nil {
-			sm.ServerEncrypted = v
+		if v := fileOutput.IsServerEncrypted; v != nil {
+			sm.ServerEncrypted = *v
+		}
+		if v := fileOutput.CacheControl; v != nil {
+			sm.CacheControl = *v
+		}
+		if v := fileOutput.ContentDisposition; v != nil {
+			sm.ContentDisposition = *v
+		}
+		if v := fileOutput.ContentEncoding; v != nil {
+			sm.ContentEncoding = *v
+		}
+		if v := fileOutput.ContentLanguage; v != nil {
+			sm.ContentLanguage = *v
+		}
+		formatSMBProperties(&sm, smbProperties{
+			attributes:    fileOutput.FileAttributes,
+			changeTime:    fileOutput.FileChangeTime,
+			creationTime:  fileOutput.FileCreationTime,
+			id:            fileOutput.ID,
+			lastWriteTime: fileOutput.FileLastWriteTime,
+			parentID:      fileOutput.ParentID,
+			permissionKey: fileOutput.FilePermissionKey,
+		})
+		if opt.HasResolveFilePermission && opt.ResolveFilePermission {
+			sm.FilePermission, err = s.getFilePermission(ctx, sm.FilePermissionKey)
+			if err != nil {
+				return nil, err
+			}
 		}
 		o.SetSystemMetadata(sm)
 	}
 
+	if cacheable {
+		s.statCache.set(rp, mode, o)
+	}
 	return o, nil
 }
 
 func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int64, opt pairStorageWrite) (n int64, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+	err = s.checkPath(path)
+	if err != nil {
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(s.getAbsPath(path))
+
+	ctx, span := s.startSpan(ctx, "write", path)
+	ctx, cancel := withTimeout(ctx, opt.HasTimeout, opt.Timeout)
+	defer cancel()
+	span.SetAttributes(attribute.Int64("azfile.size", size))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	// File service doesn't support conditional create, so the file is created by rename
+	// which could fail if the file exists.
+	if (opt.HasAtomicWrite && opt.AtomicWrite) || (opt.HasCreateOnly && opt.CreateOnly) {
+		return s.writeAtomic(ctx, path, r, size, opt)
+	}
+
+	// The wrappers don't buffer, so the content could be skipped on the source
+	// reader while resuming from checkpoint.
+	src := r
+	r = s.limitReader(ctx, r)
 	if opt.HasIoCallback {
 		r = iowrap.CallbackReader(r, opt.IoCallback)
 	}
 
-	headers := azfile.FileHTTPHeaders{}
+	if opt.HasPreserveFileInfo && opt.PreserveFileInfo {
+		err = preserveFileInfo(src, &opt)
+		if err != nil {
+			return 0, err
+		}
+	}
 
-	if opt.HasContentType {
-		headers.ContentType = opt.ContentType
+	chunkSize, err := parseChunkSize(opt.HasChunkSize, opt.ChunkSize)
+	if err != nil {
+		return 0, err
 	}
 
-	// `Create` only initializes the file.
-	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/create-file
-	_, err = s.client.NewFileURL(path).Create(ctx, size, headers, nil)
+	cond := conditions{
+		hasIfMatch:           opt.HasIfMatch,
+		ifMatch:              opt.IfMatch,
+		hasIfNoneMatch:       opt.HasIfNoneMatch,
+		ifNoneMatch:          opt.IfNoneMatch,
+		hasIfModifiedSince:   opt.HasIfModifiedSince,
+		ifModifiedSince:      opt.IfModifiedSince,
+		hasIfUnmodifiedSince: opt.HasIfUnmodifiedSince,
+		ifUnmodifiedSince:    opt.IfUnmodifiedSince,
+	}
+	// Hold a lease while writing, so that the file could not be changed by others after checked.
+	lease, unlock, err := s.lockConditions(ctx, path, cond, opt.HasLeaseID, opt.LeaseID)
 	if err != nil {
 		return 0, err
 	}
+	defer unlock()
+
+	client := s.fileClient(path)
+
+	// With offset, we will patch the content of an existing file in place instead of recreating it.
+	if opt.HasOffset {
+		if size < 0 {
+			return 0, fmt.Errorf("size must be known while writing with offset")
+		}
+		// Every segment of encrypted file is sealed with its position, so it can't be patched.
+		if s.encryptionKey != nil {
+			return 0, fmt.Errorf("%w: write with offset while client-side encryption enabled", services.ErrCapabilityInsufficient)
+		}
+		// The gzip stream of compressed file could not be patched either.
+		if s.compression != "" {
+			return 0, fmt.Errorf("%w: write with offset while compression enabled", services.ErrCapabilityInsufficient)
+		}
+
+		output, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		// `UploadRange` could not write beyond the end of file, so we need to extend the file first.
+		if opt.Offset+size > *output.ContentLength {
+			if opt.HasCheckQuota && opt.CheckQuota {
+				err = s.checkQuota(ctx, opt.Offset+size-*output.ContentLength)
+				if err != nil {
+					return 0, err
+				}
+			}
+
+			_, err = client.Resize(ctx, opt.Offset+size, &file.ResizeOptions{
+				LeaseAccessConditions: lease,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		err = s.uploadRanges(ctx, client, opt.Offset, r, size, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
+		if err != nil {
+			return 0, err
+		}
+		return size, nil
+	}
 
-	body := iowrap.SizedReadSeekCloser(r, size)
+	headers := &file.HTTPHeaders{}
 
-	var transactionalMD5 []byte
+	if opt.HasCacheControl {
+		headers.CacheControl = &opt.CacheControl
+	}
+	if opt.HasContentDisposition {
+		headers.ContentDisposition = &opt.ContentDisposition
+	}
+	if opt.HasContentEncoding {
+		headers.ContentEncoding = &opt.ContentEncoding
+	}
+	if opt.HasContentLanguage {
+		headers.ContentLanguage = &opt.ContentLanguage
+	}
+	if opt.HasContentType {
+		headers.ContentType = &opt.ContentType
+	}
 	if opt.HasContentMd5 {
-		transactionalMD5, err = base64.StdEncoding.DecodeString(opt.ContentMd5)
+		headers.ContentMD5, err = base64.StdEncoding.DecodeString(opt.ContentMd5)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	permissions, err := s.formatFilePermission(ctx, opt.HasFilePermission, opt.FilePermission, opt.HasFilePermissionKey, opt.FilePermissionKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var metadata map[string]*string
+	if opt.HasUserMetadata {
+		metadata = formatMetadata(opt.UserMetadata)
+	}
+
+	// The size of compressed content is unknown before compressed, so it's uploaded as
+	// a stream, and the size of content before compressed is returned.
+	var counter *countReader
+	if s.shouldCompress(size, opt) {
+		if opt.HasCheckpointStore {
+			return 0, fmt.Errorf("%w: checkpoint with compression", services.ErrCapabilityInsufficient)
+		}
+		counter = &countReader{r: r}
+		zr := newCompressReader(counter)
+		defer zr.Close()
+
+		r = zr
+		size = -1
+		headers.ContentEncoding = to.Ptr(s.compression)
+	}
+
+	// A negative size means the size is unknown, the content will be read until EOF,
+	// and the file will grow while the content is uploaded.
+	streaming := size < 0
+	if streaming && s.encryptionKey != nil {
+		return 0, fmt.Errorf("%w: write with unknown size while client-side encryption enabled", services.ErrCapabilityInsufficient)
+	}
+	// The space of the file being overwritten is not counted as free, and quota
+	// could not be checked for unknown size.
+	if opt.HasCheckQuota && opt.CheckQuota && !streaming {
+		err = s.checkQuota(ctx, size)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// The upload will be resumed if the file created by the interrupted upload is still there.
+	var resumed int64
+	if opt.HasCheckpointStore {
+		if streaming || s.encryptionKey != nil {
+			return 0, fmt.Errorf("%w: checkpoint with unknown size or client-side encryption", services.ErrCapabilityInsufficient)
+		}
+		resumed, err = s.loadCheckpoint(ctx, opt.CheckpointStore, path, size)
 		if err != nil {
 			return 0, err
 		}
 	}
 
+	// The content key is wrapped and stored in metadata along with the user metadata.
+	fileSize := size
+	if streaming {
+		fileSize = 0
+	} else if s.encryptionKey != nil {
+		aead, em, err := s.newEncryption(size)
+		if err != nil {
+			return 0, err
+		}
+		if metadata == nil {
+			metadata = make(map[string]*string, len(em))
+		}
+		for k, v := range em {
+			metadata[k] = v
+		}
+
+		r = newEncryptReader(r, aead, size)
+		fileSize = encryptedSize(size)
+	}
+
+	// The Content-MD5 of file is not computed by service, we compute it over the content
+	// as stored, so that it could be verified against the downloaded content.
+	var contentMD5 hash.Hash
+	computeMD5 := opt.HasComputeContentMd5 && opt.ComputeContentMd5 && !opt.HasContentMd5
+	verify := opt.HasVerifyTransfer && opt.VerifyTransfer
+	if computeMD5 || verify {
+		contentMD5 = md5.New()
+		r = io.TeeReader(r, contentMD5)
+	}
+
+	if resumed > 0 && contentMD5 != nil {
+		// The skipped content is still needed by the MD5 of the whole file.
+		_, err = io.CopyN(contentMD5, src, resumed)
+	} else if resumed > 0 {
+		err = skipReader(src, resumed)
+	} else {
+		// `Create` only initializes the file.
+		// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/create-file
+		err = s.withParentDirs(ctx, path, func() error {
+			_, err := client.Create(ctx, fileSize, &file.CreateOptions{
+				Permissions:           permissions,
+				HTTPHeaders:           headers,
+				LeaseAccessConditions: lease,
+				Metadata:              metadata,
+			})
+			return err
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+
 	// Since `Create' only initializes the file, we need to call `UploadRange' to write the contents to the file.
-	_, err = s.client.NewFileURL(path).UploadRange(ctx, 0, body, transactionalMD5)
+	if streaming {
+		size, err = s.uploadStream(ctx, client, r, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
+	} else if ra, ok := src.(readerAtSource); ok && r == src && !opt.HasCheckpointStore {
+		// r is not wrapped by io_callback, bandwidth_limit or encryption, so we could slice ranges from the source.
+		err = uploadRangesAt(ctx, client, ra, fileSize, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
+	} else if opt.HasCheckpointStore {
+		err = s.uploadRangesWithCheckpoint(ctx, opt.CheckpointStore, path, client, resumed, r, fileSize, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
+	} else {
+		err = s.uploadRanges(ctx, client, 0, r, fileSize, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if computeMD5 {
+		headers.ContentMD5 = contentMD5.Sum(nil)
+	}
+
+	// Attributes like ReadOnly will prevent the content from being written,
+	// and the last write time will be changed by uploading ranges,
+	// so we set them after all ranges uploaded.
+	if opt.HasFileAttributes || opt.HasFileCreationTime || opt.HasFileLastWriteTime || computeMD5 {
+		properties := &file.SMBProperties{}
+		if opt.HasFileAttributes {
+			properties.Attributes, err = file.ParseNTFSFileAttributes(&opt.FileAttributes)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if opt.HasFileCreationTime {
+			properties.CreationTime = &opt.FileCreationTime
+		}
+		if opt.HasFileLastWriteTime {
+			properties.LastWriteTime = &opt.FileLastWriteTime
+		}
+
+		_, err = client.SetHTTPHeaders(ctx, &file.SetHTTPHeadersOptions{
+			SMBProperties:         properties,
+			HTTPHeaders:           headers,
+			LeaseAccessConditions: lease,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// The file is read back to prove that the content stored is what we sent.
+	if verify {
+		stored := fileSize
+		if streaming {
+			stored = size
+		}
+		err = s.verifyTransfer(ctx, path, client, stored, contentMD5.Sum(nil))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if opt.HasCheckpointStore {
+		err = opt.CheckpointStore.Delete(ctx, s.checkpointKey(path))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if counter != nil {
+		return counter.n, nil
+	}
+	return size, nil
+}
+
+func (s *Storage) writeAppend(ctx context.Context, o *Object, r io.Reader, size int64, opt pairStorageWriteAppend) (n int64, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(o.ID)
+
+	// The append object could be created before client-side encryption enabled, or by
+	// other clients, the content appended must not be left unencrypted.
+	if s.encryptionKey != nil {
+		return 0, fmt.Errorf("%w: client-side encryption", services.ErrCapabilityInsufficient)
+	}
+	// Neither could the content appended to a compressed file be decompressed.
+	if s.compression != "" {
+		return 0, fmt.Errorf("%w: compression", services.ErrCapabilityInsufficient)
+	}
+
+	offset, ok := o.GetAppendOffset()
+	if !ok {
+		err = fmt.Errorf("append offset is not set")
+		return
+	}
+
+	r = s.limitReader(ctx, r)
+	if opt.HasIoCallback {
+		r = iowrap.CallbackReader(r, opt.IoCallback)
+	}
+
+	chunkSize, err := parseChunkSize(opt.HasChunkSize, opt.ChunkSize)
+	if err != nil {
+		return 0, err
+	}
+
+	lease := formatLeaseAccessConditions(opt.HasLeaseID, opt.LeaseID)
+	client := s.fileClient(o.Path)
+
+	// Grow the file before uploading, ranges could not be written beyond the end of the file.
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/set-file-properties
+	_, err = client.Resize(ctx, offset+size, &file.ResizeOptions{
+		LeaseAccessConditions: lease,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.uploadRanges(ctx, client, offset, r, size, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, lease)
 	if err != nil {
 		return 0, err
 	}
 
+	o.SetAppendOffset(offset + size)
+
 	return size, nil
 }
+
+func (s *Storage) writeMultipart(ctx context.Context, o *Object, r io.Reader, size int64, index int, opt pairStorageWriteMultipart) (n int64, part *Part, err error) {
+	if s.readOnly {
+		err = ErrReadOnly
+		return
+	}
+
+	ctx = withClientRequestID(ctx, opt.HasClientRequestID, opt.ClientRequestID)
+	ctx = withCallOptions(ctx, opt.HasCallOptions, opt.CallOptions)
+
+	defer s.statCache.invalidate(o.ID)
+
+	if !o.Mode.IsPart() {
+		err = fmt.Errorf("object is not a part object")
+		return
+	}
+
+	partSize, err := parseMultipartID(o.MustGetMultipartID())
+	if err != nil {
+		return
+	}
+	if size > partSize {
+		err = fmt.Errorf("size %d exceeds the part size %d", size, partSize)
+		return
+	}
+
+	r = s.limitReader(ctx, r)
+	if opt.HasIoCallback {
+		r = iowrap.CallbackReader(r, opt.IoCallback)
+	}
+
+	chunkSize, err := parseChunkSize(opt.HasChunkSize, opt.ChunkSize)
+	if err != nil {
+		return
+	}
+
+	err = s.uploadRanges(ctx, s.fileClient(o.Path), int64(index)*partSize, r, size, chunkSize, parseConcurrency(opt.HasConcurrency, opt.Concurrency), opt.HasTransactionalCRC64 && opt.TransactionalCRC64, nil)
+	if err != nil {
+		return
+	}
+
+	part = &Part{
+		Index: index,
+		Size:  size,
+	}
+
+	return size, part, nil
+}
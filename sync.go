@@ -0,0 +1,363 @@
+package azfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// SyncOpType is the type of operation planned by PlanSync.
+type SyncOpType int
+
+const (
+	// SyncOpUpload uploads a local file to the destination.
+	SyncOpUpload SyncOpType = iota + 1
+	// SyncOpCopy copies a file from the source storager to the destination.
+	SyncOpCopy
+	// SyncOpDelete deletes a file from the destination which doesn't exist in the source.
+	SyncOpDelete
+)
+
+// SyncOp is an operation planned by PlanSync.
+type SyncOp struct {
+	Type SyncOpType
+	// Path is relative to both the source and the destination, separated by "/".
+	Path string
+	// Size and LastModified are the source file's, they are zero for SyncOpDelete.
+	Size         int64
+	LastModified time.Time
+}
+
+// SyncPlan is the operations to make the destination the same as the source.
+type SyncPlan struct {
+	Ops []SyncOp
+
+	src SyncSource
+	dst string
+}
+
+// SyncSource is the source of sync, which could be created by LocalSyncSource or
+// StoragerSyncSource.
+type SyncSource interface {
+	// list will return all files under the source keyed by the relative path.
+	list(ctx context.Context) (map[string]syncEntry, error)
+	// open will return the content of the file at the relative path.
+	open(ctx context.Context, path string) (io.ReadCloser, error)
+	opType() SyncOpType
+}
+
+// syncEntry is a file in the source or destination of sync.
+type syncEntry struct {
+	size         int64
+	lastModified time.Time
+}
+
+// equal will check whether the files are the same by size and last modified time.
+//
+// The time is compared in the precision of SMB, which is 100 nanoseconds.
+func (e syncEntry) equal(v syncEntry) bool {
+	return e.size == v.size &&
+		e.lastModified.Truncate(100*time.Nanosecond).Equal(v.lastModified.Truncate(100*time.Nanosecond))
+}
+
+// PlanSync will compare the source with dst, and return the operations to make them
+// the same.
+//
+// This function will create a context by default.
+func (s *Storage) PlanSync(src SyncSource, dst string, deleteExtra bool) (plan *SyncPlan, err error) {
+	return s.PlanSyncWithContext(context.Background(), src, dst, deleteExtra)
+}
+
+// PlanSyncWithContext will compare the source with dst, and return the operations to make
+// them the same.
+//
+// Files are compared by size and last modified time, the last write time of the files in
+// dst is set to the last modified time of the source while applied. All files under dst
+// are listed along with their properties, so no stat is needed. Files in dst which don't
+// exist in the source are only deleted if deleteExtra is true.
+func (s *Storage) PlanSyncWithContext(ctx context.Context, src SyncSource, dst string, deleteExtra bool) (plan *SyncPlan, err error) {
+	defer func() {
+		err = s.formatError("plan_sync", err, dst)
+	}()
+
+	err = s.checkPathMapper("sync")
+	if err != nil {
+		return nil, err
+	}
+
+	// The size listed is the size of encrypted content, which could not be compared.
+	if s.encryptionKey != nil {
+		return nil, fmt.Errorf("%w: sync while client-side encryption enabled", services.ErrCapabilityInsufficient)
+	}
+	// The size listed is the size of compressed content, every file would be uploaded again.
+	if s.compression != "" {
+		return nil, fmt.Errorf("%w: sync while compression enabled", services.ErrCapabilityInsufficient)
+	}
+
+	srcEntries, err := src.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dstEntries, err := s.syncList(ctx, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = &SyncPlan{src: src, dst: dst}
+	for path, e := range srcEntries {
+		if v, ok := dstEntries[path]; ok && e.equal(v) {
+			continue
+		}
+		plan.Ops = append(plan.Ops, SyncOp{
+			Type:         src.opType(),
+			Path:         path,
+			Size:         e.size,
+			LastModified: e.lastModified,
+		})
+	}
+	if deleteExtra {
+		for path := range dstEntries {
+			if _, ok := srcEntries[path]; !ok {
+				plan.Ops = append(plan.Ops, SyncOp{Type: SyncOpDelete, Path: path})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// ApplySync will execute the operations of plan.
+//
+// This function will create a context by default.
+func (s *Storage) ApplySync(plan *SyncPlan, pairs ...types.Pair) (err error) {
+	return s.ApplySyncWithContext(context.Background(), plan, pairs...)
+}
+
+// ApplySyncWithContext will execute the operations of plan.
+//
+// The pairs of Write are supported and applied to every uploaded file. concurrency
+// decides how many operations will be executed at the same time, as well as how many
+// ranges of every file. The parent directories are created as needed, and the
+// directories left empty by deletes are kept unless virtual_dir is enabled. With
+// dry_run, the operations are reported instead of executed.
+func (s *Storage) ApplySyncWithContext(ctx context.Context, plan *SyncPlan, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("apply_sync", err, plan.dst)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	pairs = append(pairs, s.defaultPairs.Write...)
+	opt, err := s.parsePairStorageWrite(pairs)
+	if err != nil {
+		return err
+	}
+	if opt.HasOffset {
+		return fmt.Errorf("apply sync with offset")
+	}
+
+	pool, ctx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+	for _, op := range plan.Ops {
+		op := op
+		path := joinPath(plan.dst, op.Path)
+
+		// Deletes are skipped by delete itself.
+		if op.Type != SyncOpDelete {
+			name := DryRunOpUpload
+			if op.Type == SyncOpCopy {
+				name = DryRunOpCopy
+			}
+			if s.dryRun(DryRunOp{Op: name, Path: path, Src: op.Path}) {
+				continue
+			}
+		}
+
+		ok := pool.Go(ctx, func() error {
+			if op.Type == SyncOpDelete {
+				return s.delete(ctx, path, pairStorageDelete{})
+			}
+
+			// The last write time is used to compare the files in the next sync.
+			opt := opt
+			opt.HasFileLastWriteTime = true
+			opt.FileLastWriteTime = op.LastModified
+
+			return s.withParentDirsIf(ctx, true, path, func() error {
+				r, err := plan.src.open(ctx, op.Path)
+				if err != nil {
+					return err
+				}
+				defer r.Close()
+
+				_, err = s.write(ctx, path, r, op.Size, opt)
+				return err
+			})
+		})
+		if !ok {
+			break
+		}
+	}
+	return pool.Wait()
+}
+
+// syncList will list all files under dir recursively, keyed by the path relative to dir.
+func (s *Storage) syncList(ctx context.Context, dir string) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		rel := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+
+		pager := s.dirClient(joinPath(dir, rel)).NewListFilesAndDirectoriesPager(&directory.ListFilesAndDirectoriesOptions{
+			Include: directory.ListFilesInclude{
+				Timestamps: true,
+			},
+			MaxResults: to.Ptr(int32(maxListPageSize)),
+		})
+		for pager.More() {
+			output, err := pager.NextPage(ctx)
+			if err != nil {
+				// Nothing has been synced into dst yet.
+				if rel == "" && checkError(err, fileNotFound) {
+					return entries, nil
+				}
+				return nil, err
+			}
+
+			for _, v := range output.Segment.Directories {
+				dirs = append(dirs, joinPath(rel, *v.Name))
+			}
+			for _, v := range output.Segment.Files {
+				var e syncEntry
+				if v.Properties != nil {
+					e.size = deref(v.Properties.ContentLength)
+					e.lastModified = deref(v.Properties.LastWriteTime)
+				}
+				entries[joinPath(rel, *v.Name)] = e
+			}
+		}
+	}
+	return entries, nil
+}
+
+// LocalSyncSource will return a SyncSource of the files under the local directory,
+// symbolic links and other irregular files are skipped.
+func LocalSyncSource(dir string) SyncSource {
+	return localSyncSource(dir)
+}
+
+type localSyncSource string
+
+func (dir localSyncSource) list(ctx context.Context) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+
+	err := filepath.WalkDir(string(dir), func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(string(dir), name)
+		if err != nil {
+			return err
+		}
+		entries[filepath.ToSlash(rel)] = syncEntry{
+			size:         fi.Size(),
+			lastModified: fi.ModTime(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (dir localSyncSource) open(ctx context.Context, path string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(string(dir), filepath.FromSlash(path)))
+}
+
+func (dir localSyncSource) opType() SyncOpType {
+	return SyncOpUpload
+}
+
+// StoragerSyncSource will return a SyncSource of the files under path of store, which
+// must support listing in prefix mode.
+func StoragerSyncSource(store types.Storager, path string) SyncSource {
+	return &storagerSyncSource{store: store, path: path}
+}
+
+type storagerSyncSource struct {
+	store types.Storager
+	path  string
+}
+
+func (src *storagerSyncSource) list(ctx context.Context) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+
+	prefix := src.path
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	it, err := src.store.ListWithContext(ctx, prefix, ps.WithListMode(types.ListModePrefix))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		o, err := it.Next()
+		if errors.Is(err, types.IterateDone) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if o.Mode.IsDir() {
+			continue
+		}
+
+		var e syncEntry
+		e.size, _ = o.GetContentLength()
+		e.lastModified, _ = o.GetLastModified()
+		entries[strings.TrimPrefix(o.Path, prefix)] = e
+	}
+	return entries, nil
+}
+
+// open will read the file in another goroutine, which stops once the returned reader is closed.
+func (src *storagerSyncSource) open(ctx context.Context, path string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := src.store.ReadWithContext(ctx, joinPath(src.path, path), pw)
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+func (src *storagerSyncSource) opType() SyncOpType {
+	return SyncOpCopy
+}
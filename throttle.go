@@ -0,0 +1,97 @@
+package azfile
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// minThrottleBackoff is the backoff after the first throttled response.
+	minThrottleBackoff = 500 * time.Millisecond
+	// maxThrottleBackoff is the upper bound of the backoff, Retry-After returned by service is still respected.
+	maxThrottleBackoff = 30 * time.Second
+)
+
+// throttlePolicy will back off all requests sent by the service once any of them
+// has been throttled, so that concurrent requests don't keep hammering the share.
+//
+// The backoff will be doubled for every throttled response, and halved for every
+// successful response.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/files/storage-troubleshooting-files-performance#cause-1-share-was-throttled
+type throttlePolicy struct {
+	mu      sync.Mutex
+	until   time.Time
+	backoff time.Duration
+}
+
+// Do implements policy.Policy
+func (p *throttlePolicy) Do(req *policy.Request) (*http.Response, error) {
+	if d := p.wait(); d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-req.Raw().Context().Done():
+			t.Stop()
+			return nil, req.Raw().Context().Err()
+		case <-t.C:
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		p.throttled(resp.Header.Get("Retry-After"))
+	} else {
+		p.succeeded()
+	}
+	return resp, nil
+}
+
+// wait will return how long the request should wait before being sent.
+func (p *throttlePolicy) wait() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return time.Until(p.until)
+}
+
+func (p *throttlePolicy) throttled(retryAfter string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.backoff *= 2
+	if p.backoff < minThrottleBackoff {
+		p.backoff = minThrottleBackoff
+	}
+	if p.backoff > maxThrottleBackoff {
+		p.backoff = maxThrottleBackoff
+	}
+
+	d := p.backoff
+	// Retry-After is expressed in seconds.
+	if v, err := strconv.Atoi(retryAfter); err == nil && time.Duration(v)*time.Second > d {
+		d = time.Duration(v) * time.Second
+	}
+
+	// Concurrent throttled responses should not push the deadline further than needed.
+	if until := time.Now().Add(d); until.After(p.until) {
+		p.until = until
+	}
+}
+
+func (p *throttlePolicy) succeeded() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.backoff /= 2
+	if p.backoff < minThrottleBackoff {
+		p.backoff = 0
+	}
+}
@@ -0,0 +1,54 @@
+package azfile
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// maxServerTimeout is the maximum value of the timeout query parameter accepted by file service.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/setting-timeouts-for-file-service-operations
+const maxServerTimeout = 30 * time.Second
+
+type serverTimeoutKey struct{}
+
+// withTimeout will apply the timeout to ctx, and ask the service to abort the
+// request on server side after the same duration.
+//
+// The returned cancel func must be called after the operation finished.
+func withTimeout(ctx context.Context, has bool, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if !has || timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	ctx = context.WithValue(ctx, serverTimeoutKey{}, timeout)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// serverTimeoutPolicy will set the timeout query parameter for requests whose
+// context carries the timeout set by withTimeout.
+type serverTimeoutPolicy struct{}
+
+// Do implements policy.Policy
+func (serverTimeoutPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	timeout, ok := raw.Context().Value(serverTimeoutKey{}).(time.Duration)
+	if ok {
+		if timeout > maxServerTimeout {
+			timeout = maxServerTimeout
+		}
+		// The timeout is expressed in seconds, a value less than 1 second is not allowed.
+		if seconds := int64(timeout / time.Second); seconds > 0 {
+			q := raw.URL.Query()
+			q.Set("timeout", strconv.FormatInt(seconds, 10))
+			raw.URL.RawQuery = q.Encode()
+		}
+	}
+
+	return req.Next()
+}
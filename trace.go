@@ -0,0 +1,49 @@
+package azfile
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerProvider is the OpenTelemetry tracer provider used to trace storage operations.
+type TracerProvider = trace.TracerProvider
+
+// tracerName is the instrumentation name of the spans.
+const tracerName = "github.com/beyondstorage/go-service-azfile"
+
+func newTracer(has bool, tp TracerProvider) trace.Tracer {
+	if !has {
+		// Global tracer provider is a no-op provider until it's set by users.
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// startSpan will start a span for the storage operation on path.
+//
+// The operation name is carried by the returned ctx to label the metrics of requests.
+func (s *Storage) startSpan(ctx context.Context, op string, path string) (context.Context, trace.Span) {
+	ctx = withOperation(ctx, op)
+	return s.tracer.Start(ctx, "azfile."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("azfile.share", s.name),
+			attribute.String("azfile.path", s.getAbsPath(path)),
+		),
+	)
+}
+
+// endSpan will record the result of the operation and end the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
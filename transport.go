@@ -0,0 +1,77 @@
+package azfile
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes the connections of the default transport, the zero value
+// of every field keeps the default of net/http.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost should be no less than the concurrency of operations, otherwise
+	// the connections exceeding it are closed after every request. Default to 2.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections including the ones in use, zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept. Default to 90 seconds.
+	IdleConnTimeout time.Duration
+
+	// DialTimeout is the timeout of establishing TCP connections. Default to 30 seconds.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the timeout of TLS handshakes. Default to 10 seconds.
+	TLSHandshakeTimeout time.Duration
+	// TLSSessionCacheSize enables TLS session resumption with a cache of the size, so that
+	// new connections skip the full handshake.
+	TLSSessionCacheSize int
+
+	// DisableHTTP2 will send requests with HTTP/1.1 only. Requests of an HTTP/2 connection
+	// share its flow control window, which could limit the throughput of concurrent uploads.
+	DisableHTTP2 bool
+}
+
+// newTransport will create a transport from the default transport with opt.
+func newTransport(opt TransportOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if opt.DialTimeout > 0 {
+		dialer.Timeout = opt.DialTimeout
+	}
+	t.DialContext = dialer.DialContext
+
+	if opt.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opt.MaxIdleConnsPerHost
+		// The idle connections of all hosts are limited too.
+		if t.MaxIdleConns > 0 && t.MaxIdleConns < opt.MaxIdleConnsPerHost {
+			t.MaxIdleConns = opt.MaxIdleConnsPerHost
+		}
+	}
+	if opt.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = opt.MaxConnsPerHost
+	}
+	if opt.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opt.IdleConnTimeout
+	}
+	if opt.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = opt.TLSHandshakeTimeout
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	// Same as the default transport of azcore.
+	t.TLSClientConfig.MinVersion = tls.VersionTLS12
+	if opt.TLSSessionCacheSize > 0 {
+		t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(opt.TLSSessionCacheSize)
+	}
+	if opt.DisableHTTP2 {
+		// A non-nil empty map disables the HTTP/2 upgrade of TLS connections.
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return t
+}
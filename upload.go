@@ -0,0 +1,91 @@
+package azfile
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"hash/crc64"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// readerAtSource marks the content passed by WriteFromReaderAt, so that write
+// could slice ranges from it directly.
+type readerAtSource struct {
+	*io.SectionReader
+}
+
+// WriteFromReaderAt will write size bytes read from r into path.
+//
+// This function will create a context by default.
+func (s *Storage) WriteFromReaderAt(path string, r io.ReaderAt, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteFromReaderAtWithContext(context.Background(), path, r, size, pairs...)
+}
+
+// WriteFromReaderAtWithContext will write the first size bytes of r into path.
+//
+// The pairs of Write are supported. Instead of copying the content into buffers,
+// every range is uploaded from its section of r directly, so r must be safe for
+// concurrent ReadAt like *os.File. With io_callback or bandwidth_limit, the content
+// will be read sequentially like Write.
+func (s *Storage) WriteFromReaderAtWithContext(ctx context.Context, path string, r io.ReaderAt, size int64, pairs ...types.Pair) (n int64, err error) {
+	if size < 0 {
+		return 0, s.formatError("write_from_reader_at", fmt.Errorf("size %d is invalid", size), path)
+	}
+	return s.WriteWithContext(ctx, path, readerAtSource{io.NewSectionReader(r, 0, size)}, size, pairs...)
+}
+
+// uploadRangesAt will upload size bytes of r start from 0 to the file in ranges concurrently.
+func uploadRangesAt(ctx context.Context, client *file.Client, r io.ReaderAt, size int64, chunkSize int64, concurrency int, useCRC64 bool, lease *file.LeaseAccessConditions) error {
+	pool, ctx := newWorkerPool(ctx, concurrency)
+
+	for offset := int64(0); offset < size; offset += chunkSize {
+		offset, n := offset, chunkSize
+		if offset+n > size {
+			n = size - offset
+		}
+
+		ok := pool.Go(ctx, func() error {
+			return uploadRangeAt(ctx, client, offset, io.NewSectionReader(r, offset, n), useCRC64, lease)
+		})
+		if !ok {
+			break
+		}
+	}
+
+	return pool.Wait()
+}
+
+// uploadRangeAt is the same as uploadRange, but reads the content from section.
+//
+// The section will be read twice, once for the checksum and once for the request.
+func uploadRangeAt(ctx context.Context, client *file.Client, offset int64, section *io.SectionReader, useCRC64 bool, lease *file.LeaseAccessConditions) error {
+	options := &file.UploadRangeOptions{
+		LeaseAccessConditions: lease,
+	}
+
+	if useCRC64 {
+		h := crc64.New(crc64Table)
+		if _, err := io.Copy(h, section); err != nil {
+			return err
+		}
+		ctx = withContentCRC64Sum(ctx, h.Sum64())
+	} else {
+		h := md5.New()
+		if _, err := io.Copy(h, section); err != nil {
+			return err
+		}
+		options.TransactionalValidation = file.TransferValidationTypeMD5(h.Sum(nil))
+	}
+
+	if _, err := section.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err := client.UploadRange(ctx, offset, streaming.NopCloser(section), options)
+	return err
+}
@@ -0,0 +1,137 @@
+package azfile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// UploadDir will upload all files under the local directory into dst, the
+// structure of directories will be kept.
+//
+// This function will create a context by default.
+func (s *Storage) UploadDir(local, dst string, pairs ...types.Pair) (err error) {
+	return s.UploadDirWithContext(context.Background(), local, dst, pairs...)
+}
+
+// UploadDirWithContext will upload all files under the local directory into dst, the
+// structure of directories will be kept.
+//
+// The pairs of Write are supported and applied to every file. concurrency decides how
+// many files will be uploaded at the same time, as well as how many ranges of every file.
+// With preserve_file_info, the last write time and the ReadOnly attribute of local
+// files will be kept. Symbolic links and other irregular files are skipped.
+func (s *Storage) UploadDirWithContext(ctx context.Context, local, dst string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("upload_dir", err, dst)
+	}()
+
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	pairs = append(pairs, s.defaultPairs.Write...)
+	opt, err := s.parsePairStorageWrite(pairs)
+	if err != nil {
+		return err
+	}
+	if opt.HasOffset {
+		return fmt.Errorf("upload dir with offset")
+	}
+
+	pool, ctx := newWorkerPool(ctx, parseConcurrency(opt.HasConcurrency, opt.Concurrency))
+
+	// Directories are walked in lexical order, so that parents are created before their children.
+	err = filepath.WalkDir(local, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(local, name)
+		if err != nil {
+			return err
+		}
+		path := dst
+		if rel != "." {
+			path = joinPath(dst, filepath.ToSlash(rel))
+		}
+
+		if d.IsDir() {
+			_, err = s.createDir(ctx, path, pairStorageCreateDir{
+				HasCreateParents: rel == ".",
+				CreateParents:    true,
+			})
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		ok := pool.Go(ctx, func() error {
+			return s.uploadLocalFile(ctx, name, path, opt)
+		})
+		if !ok {
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		pool.setError(err)
+	}
+
+	return pool.Wait()
+}
+
+// uploadLocalFile will upload the local file into path, the ranges are read from the
+// file directly like WriteFromReaderAt.
+func (s *Storage) uploadLocalFile(ctx context.Context, name, path string, opt pairStorageWrite) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.write(ctx, path, readerAtSource{io.NewSectionReader(f, 0, fi.Size())}, fi.Size(), opt)
+	return err
+}
+
+// preserveFileInfo will set the last write time and attributes of the file from the
+// local file which src is read from, the values given by pairs are kept.
+//
+// src is ignored if it's not read from a file like *os.File.
+func preserveFileInfo(src io.Reader, opt *pairStorageWrite) error {
+	var v interface{} = src
+	if ra, ok := src.(readerAtSource); ok {
+		v, _, _ = ra.Outer()
+	}
+	f, ok := v.(interface{ Stat() (fs.FileInfo, error) })
+	if !ok {
+		return nil
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !opt.HasFileLastWriteTime {
+		opt.HasFileLastWriteTime = true
+		opt.FileLastWriteTime = fi.ModTime()
+	}
+	// The other attributes have no equivalent in the mode of local files.
+	if !opt.HasFileAttributes && fi.Mode().Perm()&0o200 == 0 {
+		opt.HasFileAttributes = true
+		opt.FileAttributes = "ReadOnly"
+	}
+	return nil
+}
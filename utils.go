@@ -1,12 +1,30 @@
 package azfile
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	pathpkg "path"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/Azure/azure-storage-file-go/azfile"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/service"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/share"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/beyondstorage/go-endpoint"
 	ps "github.com/beyondstorage/go-storage/v4/pairs"
@@ -15,51 +33,274 @@ import (
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
+// RetryOptions is the retry policy of requests, including max retries, retry delays and try timeout.
+//
+// Zero values will be replaced by SDK defaults, set MaxRetries to -1 to disable retry.
+type RetryOptions = policy.RetryOptions
+
+// Service is the azfile service.
+type Service struct {
+	service *service.Client
+	tracer  trace.Tracer
+	// sharedKey is only set if the service is authorized by shared key, it's used to sign SAS.
+	sharedKey *service.SharedKeyCredential
+	// allowTrailingDot keeps the trailing dots of names, which are trimmed by service by default.
+	allowTrailingDot bool
+
+	defaultPairs DefaultServicePairs
+	features     ServiceFeatures
+
+	types.UnimplementedServicer
+}
+
+// String implements Servicer.String
+func (s *Service) String() string {
+	return "Servicer azfile"
+}
+
+// ServiceClient will return the SDK client of the file service, which carries the
+// credential and pipeline of the service.
+//
+// It's an escape hatch for operations not covered by Servicer, the behavior of
+// requests sent by it is not guaranteed by us.
+func (s *Service) ServiceClient() *service.Client {
+	return s.service
+}
+
 // Storage is the azfile client.
 type Storage struct {
-	client azfile.DirectoryURL
+	service *service.Client
+	share   *share.Client
+	client  *directory.Client
+	tracer  trace.Tracer
+
+	name     string
+	snapshot string
+	workDir  string
+
+	// statCache is nil if stat cache is disabled.
+	statCache *statCache
+
+	// encryptionKey is not nil if client-side encryption is enabled.
+	encryptionKey         *EncryptionKey
+	encryptionKeyResolver EncryptionKeyResolver
 
-	workDir string
+	// compression is empty if compression is disabled.
+	compression          string
+	compressionThreshold int64
+
+	// limiter is nil if bandwidth_limit is not set.
+	limiter *rateLimiter
+	buffers *bufferPool
+
+	// dryRunFunc is not nil if dry run is enabled.
+	dryRunFunc DryRunFunc
+	// readOnly rejects all mutating operations before any request sent.
+	readOnly bool
+	// pathValidation is empty if path validation is disabled.
+	pathValidation string
+	// allowTrailingDot is inherited from service, names ending with dot are valid with it.
+	allowTrailingDot bool
+	// pathMapper is nil if the paths of files are stored as is.
+	pathMapper PathMapper
 
 	defaultPairs DefaultStoragePairs
 	features     StorageFeatures
 
 	types.UnimplementedStorager
+	types.UnimplementedCopier
+	types.UnimplementedMover
+	types.UnimplementedAppender
+	types.UnimplementedMultiparter
+	types.UnimplementedHTTPSigner
 	types.UnimplementedDirer
+	types.UnimplementedFetcher
+	types.UnimplementedLinker
 }
 
 // String implements Storager.String
 func (s *Storage) String() string {
-	return fmt.Sprintf("Storager azfile {WorkDir: %s}", s.workDir)
+	if s.snapshot != "" {
+		return fmt.Sprintf("Storager azfile {Name: %s, Snapshot: %s, WorkDir: %s}", s.name, s.snapshot, s.workDir)
+	}
+	return fmt.Sprintf("Storager azfile {Name: %s, WorkDir: %s}", s.name, s.workDir)
+}
+
+// ShareClient will return the SDK client of the share, which carries the credential
+// and pipeline of the storage, and the snapshot if share_snapshot is set.
+//
+// It's an escape hatch for operations not covered by Storager, the work dir is not
+// applied to it.
+func (s *Storage) ShareClient() *share.Client {
+	return s.share
+}
+
+// New will create a new azfile service.
+func New(pairs ...types.Pair) (types.Servicer, types.Storager, error) {
+	return newServicerAndStorager(pairs...)
+}
+
+// NewServicer will create Servicer only.
+func NewServicer(pairs ...types.Pair) (types.Servicer, error) {
+	return newServicer(pairs...)
 }
 
 // NewStorager will create Storager only.
 func NewStorager(pairs ...types.Pair) (types.Storager, error) {
-	return newStorager(pairs...)
+	_, store, err := newServicerAndStorager(pairs...)
+	return store, err
 }
 
-// newStorager will create a storage client.
-func newStorager(pairs ...types.Pair) (store *Storage, err error) {
+func newServicer(pairs ...types.Pair) (srv *Service, err error) {
 	defer func() {
 		if err != nil {
-			err = services.InitError{Op: "new_storager", Type: Type, Err: formatError(err), Pairs: pairs}
+			err = services.InitError{Op: "new_servicer", Type: Type, Err: formatError(err), Pairs: pairs}
 		}
 	}()
 
-	opt, err := parsePairStorageNew(pairs)
+	opt, err := parsePairServiceNew(pairs)
 	if err != nil {
 		return nil, err
 	}
 
-	store = &Storage{
-		workDir: "/",
+	var primaryURL *url.URL
+	var sharedKey *service.SharedKeyCredential
+	if opt.HasConnectionString {
+		primaryURL, sharedKey, err = parseConnectionString(opt.ConnectionString)
+		if err != nil {
+			return nil, err
+		}
+	} else if opt.HasEndpoint {
+		primaryURL, err = parseEndpoint(opt.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		// Emulators like Azurite use path-style url which carries the account name in path.
+		//
+		// ref: https://docs.microsoft.com/en-us/azure/storage/common/storage-use-azurite#connection-strings
+		if opt.HasAccountName {
+			primaryURL.Path = "/" + opt.AccountName
+		}
+	} else if opt.HasAccountName {
+		suffix := defaultEndpointSuffix
+		if opt.HasEndpointSuffix {
+			suffix = opt.EndpointSuffix
+		}
+
+		primaryURL, err = formatEndpoint("https", opt.AccountName, suffix)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, services.PairRequiredError{Keys: []string{"endpoint"}}
 	}
 
-	if opt.HasWorkDir {
-		store.workDir = opt.WorkDir
+	options := &service.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Retry: policy.RetryOptions{
+				// A value of -1 means 1 try and no retries.
+				MaxRetries: -1,
+				// Set a long enough timeout to adopt our timeout control.
+				// This value could be adjusted to context deadline if request context has a deadline set.
+				TryTimeout: 720 * time.Hour,
+			},
+		},
 	}
+	if opt.HasRetryOptions {
+		options.Retry = opt.RetryOptions
+	}
+	if opt.HasAllowTrailingDot && opt.AllowTrailingDot {
+		// Names ending with dot will be trimmed by service unless allowed explicitly,
+		// the source option applies to the source of copy and rename.
+		//
+		// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-shares--directories--files--and-metadata
+		options.AllowTrailingDot = to.Ptr(true)
+		options.AllowSourceTrailingDot = to.Ptr(true)
+	}
+	if opt.HasHTTPTransport && opt.HasTransportOptions {
+		return nil, fmt.Errorf("http_transport and transport_options could not be used together")
+	}
+	if opt.HasHTTPTransport {
+		options.Transport = &http.Client{Transport: opt.HTTPTransport}
+	}
+	if opt.HasTransportOptions {
+		options.Transport = &http.Client{Transport: newTransport(opt.TransportOptions)}
+	}
+	// The timeout, the transactional CRC64 and the client request id of operations are carried by request context.
+	options.PerCallPolicies = append(options.PerCallPolicies, serverTimeoutPolicy{}, contentCRC64Policy{}, clientRequestIDPolicy{}, callOptionsPolicy{})
+	if opt.HasAPIVersion {
+		// Use per call policy so that the version is set before the request is signed.
+		options.PerCallPolicies = append(options.PerCallPolicies, apiVersionPolicy{version: opt.APIVersion})
+	}
+	// Throttled requests will be backed off by the throttle policy, and all
+	// clients created from this service share the same backoff.
+	options.PerRetryPolicies = append(options.PerRetryPolicies, &throttlePolicy{})
+	if opt.HasMetricsCollector {
+		// Use per retry policy so that every try will be counted.
+		options.PerRetryPolicies = append(options.PerRetryPolicies, metricsPolicy{collector: opt.MetricsCollector})
+	}
+	if opt.HasRequestLogger {
+		// Use per retry policy so that every try will be logged.
+		options.PerRetryPolicies = append(options.PerRetryPolicies, requestLogPolicy{logger: opt.RequestLogger})
+	}
+
+	if opt.HasSASProvider {
+		// Use per retry policy so that the retried request will carry the fresh token.
+		options.PerRetryPolicies = append(options.PerRetryPolicies, &sasRefreshPolicy{provider: opt.SASProvider})
+	}
+
+	srv = &Service{}
+	if opt.HasTokenCredential || opt.HasTokenProvider {
+		// OAuth requests to file REST APIs must declare the intent, and only backup is supported by now.
+		options.FileRequestIntent = to.Ptr(service.ShareTokenIntentBackup)
 
-	ep, err := endpoint.Parse(opt.Endpoint)
+		var cred TokenCredential = tokenProviderCredential{provider: opt.TokenProvider}
+		if opt.HasTokenCredential {
+			cred = opt.TokenCredential
+		}
+		srv.service, err = service.NewClient(primaryURL.String(), cred, options)
+	} else if opt.HasSASProvider {
+		// The SAS token will be set by sasRefreshPolicy.
+		srv.service, err = service.NewClientWithNoCredential(primaryURL.String(), options)
+	} else {
+		// Credential pair takes precedence over the credential in connection string.
+		if opt.HasCredential {
+			sharedKey, err = parseCredential(primaryURL, opt.Credential)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if sharedKey != nil {
+			srv.sharedKey = sharedKey
+			srv.service, err = service.NewClientWithSharedKeyCredential(primaryURL.String(), sharedKey, options)
+		} else if primaryURL.RawQuery != "" {
+			// The SAS token is carried by the url, so no credential is needed.
+			srv.service, err = service.NewClientWithNoCredential(primaryURL.String(), options)
+		} else {
+			return nil, services.PairRequiredError{Keys: []string{"credential"}}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	srv.tracer = newTracer(opt.HasTracerProvider, opt.TracerProvider)
+	srv.allowTrailingDot = opt.HasAllowTrailingDot && opt.AllowTrailingDot
+
+	if opt.HasDefaultServicePairs {
+		srv.defaultPairs = opt.DefaultServicePairs
+	}
+	if opt.HasServiceFeatures {
+		srv.features = opt.ServiceFeatures
+	}
+
+	return srv, nil
+}
+
+// parseEndpoint will parse the endpoint into the url of file service.
+func parseEndpoint(cfg string) (*url.URL, error) {
+	ep, err := endpoint.Parse(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -71,43 +312,130 @@ func newStorager(pairs ...types.Pair) (store *Storage, err error) {
 	case endpoint.ProtocolHTTPS:
 		uri, _, _ = ep.HTTPS()
 	default:
-		return nil, services.PairUnsupportedError{Pair: ps.WithEndpoint(opt.Endpoint)}
+		return nil, services.PairUnsupportedError{Pair: ps.WithEndpoint(cfg)}
 	}
 
-	if strings.HasPrefix(store.workDir, "/") {
-		uri = fmt.Sprintln(uri + store.workDir)
-	} else {
-		uri = fmt.Sprintln(uri + "/" + store.workDir)
+	return url.Parse(uri)
+}
+
+// formatEndpoint will build the url of file service from the account name and endpoint suffix.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/common/storage-account-overview#storage-account-endpoints
+func formatEndpoint(protocol, accountName, suffix string) (*url.URL, error) {
+	return url.Parse(fmt.Sprintf("%s://%s.file.%s", protocol, accountName, suffix))
+}
+
+// parseCredential will parse the credential into shared key credential.
+//
+// The SAS token will be carried by the query of every request, so the shared key
+// credential will be nil to avoid signing the request again.
+func parseCredential(u *url.URL, cfg string) (*service.SharedKeyCredential, error) {
+	cred, err := credential.Parse(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	primaryURL, _ := url.Parse(uri)
+	switch cred.Protocol() {
+	case credential.ProtocolHmac:
+		return service.NewSharedKeyCredential(cred.Hmac())
+	case credential.ProtocolAPIKey:
+		// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/delegate-access-with-shared-access-signature
+		u.RawQuery = strings.TrimPrefix(cred.APIKey(), "?")
+		return nil, nil
+	default:
+		return nil, services.PairUnsupportedError{Pair: ps.WithCredential(cfg)}
+	}
+}
+
+// newServicerAndStorager will create a servicer and a storager for the share.
+func newServicerAndStorager(pairs ...types.Pair) (srv *Service, store *Storage, err error) {
+	srv, err = newServicer(pairs...)
+	if err != nil {
+		return
+	}
+
+	store, err = srv.newStorage(pairs...)
+	if err != nil {
+		err = services.InitError{Op: "new_storager", Type: Type, Err: formatError(err), Pairs: pairs}
+		return
+	}
+	return
+}
 
-	cred, err := credential.Parse(opt.Credential)
+// newStorage will create a storage client for the share.
+func (s *Service) newStorage(pairs ...types.Pair) (store *Storage, err error) {
+	opt, err := parsePairStorageNew(pairs)
 	if err != nil {
 		return nil, err
 	}
-	if cred.Protocol() != credential.ProtocolHmac {
-		return nil, services.PairUnsupportedError{Pair: ps.WithCredential(opt.Credential)}
+
+	store = &Storage{
+		service: s.service,
+		tracer:  s.tracer,
+		name:    opt.Name,
+		workDir: "/",
+
+		allowTrailingDot: s.allowTrailingDot,
 	}
 
-	credValue, err := azfile.NewSharedKeyCredential(cred.Hmac())
+	store.pathValidation, err = parsePathValidation(opt.HasPathValidation, opt.PathValidation)
 	if err != nil {
 		return nil, err
 	}
+	if opt.HasPathMapper {
+		store.pathMapper = opt.PathMapper
+	}
+	if opt.HasWorkDir {
+		// The work dir is sanitized too, so that the relative paths could be trimmed from listed paths.
+		store.workDir = store.normalizePath(formatWorkDir(opt.WorkDir))
+	}
 
-	p := azfile.NewPipeline(credValue, azfile.PipelineOptions{
-		Retry: azfile.RetryOptions{
-			// Use a fixed back-off retry policy.
-			Policy: 1,
-			// A value of 1 means 1 try and no retries.
-			MaxTries: 1,
-			// Set a long enough timeout to adopt our timeout control.
-			// This value could be adjusted to context deadline if request context has a deadline set.
-			TryTimeout: 720 * time.Hour,
-		},
-	})
+	store.share = s.service.NewShareClient(opt.Name)
+	if opt.HasShareSnapshot {
+		// All requests sent by the share client will carry the sharesnapshot query,
+		// so that read, stat and list will be served by the snapshot.
+		store.snapshot = opt.ShareSnapshot
+		store.share, err = store.share.WithSnapshot(opt.ShareSnapshot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	store.client = subdirectoryClient(store.share.NewRootDirectoryClient(), store.workDir)
 
-	store.client = azfile.NewDirectoryURL(*primaryURL, p)
+	if opt.HasStatCacheTTL && opt.StatCacheTTL > 0 {
+		store.statCache = newStatCache(opt.StatCacheTTL)
+	}
+	var bufferPoolLimit int
+	if opt.HasBufferPoolLimit {
+		bufferPoolLimit = opt.BufferPoolLimit
+	}
+	store.buffers = newBufferPool(bufferPoolLimit)
+	if opt.HasBandwidthLimit {
+		if opt.BandwidthLimit <= 0 {
+			return nil, fmt.Errorf("bandwidth limit %d is invalid", opt.BandwidthLimit)
+		}
+		store.limiter = newRateLimiter(opt.BandwidthLimit)
+	}
+	if opt.HasDryRun {
+		store.dryRunFunc = opt.DryRun
+	}
+	if opt.HasReadOnly {
+		store.readOnly = opt.ReadOnly
+	}
+	if opt.HasEncryptionKey {
+		err = validateEncryptionKey(opt.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		store.encryptionKey = &opt.EncryptionKey
+	}
+	store.compression, store.compressionThreshold, err = parseCompression(opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.HasEncryptionKeyResolver {
+		store.encryptionKeyResolver = opt.EncryptionKeyResolver
+	}
 
 	if opt.HasDefaultStoragePairs {
 		store.defaultPairs = opt.DefaultStoragePairs
@@ -119,6 +447,19 @@ func newStorager(pairs ...types.Pair) (store *Storage, err error) {
 	return store, nil
 }
 
+func (s *Service) formatError(op string, err error, name string) error {
+	if err == nil {
+		return nil
+	}
+
+	return services.ServiceError{
+		Op:       op,
+		Err:      formatError(err),
+		Servicer: s,
+		Name:     name,
+	}
+}
+
 func (s *Storage) formatError(op string, err error, path ...string) error {
 	if err == nil {
 		return nil
@@ -133,83 +474,906 @@ func (s *Storage) formatError(op string, err error, path ...string) error {
 }
 
 // formatError converts errors returned by SDK into errors defined in go-storage and go-service-*.
-// The original error SHOULD NOT be wrapped.
+// The original error SHOULD NOT be wrapped, the details are carried by StorageError instead.
+//
+// ref: [GSP-47](https://github.com/beyondstorage/specs/blob/master/rfcs/47-additional-error-specification.md)
 func formatError(err error) error {
-	if _, ok := err.(services.InternalError); ok {
+	var ie services.InternalError
+	if errors.As(err, &ie) {
 		return err
 	}
 
-	e, ok := err.(azfile.StorageError)
+	var e *azcore.ResponseError
+
+	if errors.As(err, &e) {
+		se := newStorageError(e)
 
-	if ok {
-		switch azfile.StorageErrorCodeType(e.ServiceCode()) {
+		switch fileerror.Code(e.ErrorCode) {
 		case "":
-			switch e.Response().StatusCode {
+			// HEAD requests like GetProperties don't carry the error code in body.
+			switch e.StatusCode {
 			case fileNotFound:
-				return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
+				return fmt.Errorf("%w: %w", services.ErrObjectNotExist, se)
+			case http.StatusForbidden:
+				return fmt.Errorf("%w: %w", services.ErrPermissionDenied, se)
+			case http.StatusPreconditionFailed:
+				return fmt.Errorf("%w: %w", ErrConditionNotMet, se)
+			case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+				return fmt.Errorf("%w: %w", services.ErrRequestThrottled, se)
+			case http.StatusInternalServerError:
+				return fmt.Errorf("%w: %w", services.ErrServiceInternal, se)
 			default:
-				return fmt.Errorf("%w: %v", services.ErrUnexpected, err)
+				return fmt.Errorf("%w: %w", services.ErrUnexpected, se)
 			}
-		case azfile.StorageErrorCodeResourceNotFound:
-			return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
-		case azfile.StorageErrorCodeInsufficientAccountPermissions:
-			return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+		case fileerror.ResourceNotFound, fileerror.ShareNotFound, fileerror.ParentNotFound:
+			return fmt.Errorf("%w: %w", services.ErrObjectNotExist, se)
+		case fileerror.InsufficientAccountPermissions, fileerror.AuthenticationFailed,
+			fileerror.AuthorizationFailure, fileerror.AuthorizationPermissionMismatch,
+			fileerror.AuthorizationProtocolMismatch, fileerror.AuthorizationResourceTypeMismatch,
+			fileerror.AuthorizationServiceMismatch, fileerror.AuthorizationSourceIPMismatch:
+			return fmt.Errorf("%w: %w", services.ErrPermissionDenied, se)
+		case fileerror.ConditionNotMet:
+			return fmt.Errorf("%w: %w", ErrConditionNotMet, se)
+		case fileerror.ShareSizeLimitReached:
+			return fmt.Errorf("%w: %w", ErrShareQuotaExceeded, se)
+		case fileerror.ResourceTypeMismatch:
+			return fmt.Errorf("%w: %w", services.ErrObjectModeInvalid, se)
+		case fileerror.ServerBusy:
+			return fmt.Errorf("%w: %w", services.ErrRequestThrottled, se)
+		case fileerror.InternalError, fileerror.OperationTimedOut:
+			return fmt.Errorf("%w: %w", services.ErrServiceInternal, se)
 		default:
-			return fmt.Errorf("%w: %v", services.ErrUnexpected, err)
+			return fmt.Errorf("%w: %w", services.ErrUnexpected, se)
 		}
 	}
 
 	return fmt.Errorf("%w: %v", services.ErrUnexpected, err)
 }
 
+// formatWorkDir will make sure the work dir starts and ends with "/".
+func formatWorkDir(workDir string) string {
+	if !strings.HasPrefix(workDir, "/") {
+		workDir = "/" + workDir
+	}
+	if !strings.HasSuffix(workDir, "/") {
+		workDir += "/"
+	}
+	return workDir
+}
+
 // getAbsPath will calculate object storage's abs path
+//
+// Path starts with "/" is resolved from work dir too, so that paths never escape it.
 func (s *Storage) getAbsPath(path string) string {
+	path = strings.TrimPrefix(s.normalizePath(path), "/")
+
 	prefix := strings.TrimPrefix(s.workDir, "/")
 	return prefix + path
 }
 
-// getRelPath will get object storage's rel path.
-func (s *Storage) getRelPath(path string) string {
-	prefix := strings.TrimPrefix(s.workDir, "/")
-	return strings.TrimPrefix(path, prefix)
+// signFileURL will generate a file SAS with given permissions and return the signed url.
+func (s *Storage) signFileURL(path string, expire time.Duration, perm sas.FilePermissions) (string, error) {
+	u, err := s.fileClient(path).GetSASURL(perm, time.Now().UTC().Add(expire), nil)
+	if errors.Is(err, fileerror.MissingSharedKeyCredential) {
+		return "", ErrSharedKeyRequired
+	}
+	return u, err
+}
+
+// formatCopyOptions will build the options of StartCopyFromURL.
+//
+// If keepSMBInfo is true, the SMB properties of the source will be kept, and the
+// explicit attributes and permission take precedence over the source's.
+func (s *Storage) formatCopyOptions(ctx context.Context, keepSMBInfo bool,
+	hasAttributes bool, attributes string,
+	hasPermission bool, permission string,
+	hasPermissionKey bool, permissionKey string) (*file.StartCopyFromURLOptions, error) {
+	options := &file.StartCopyFromURLOptions{}
+
+	// By default, the destination file will get default SMB properties instead of the source's.
+	smbInfo := &file.CopyFileSMBInfo{}
+	if keepSMBInfo {
+		smbInfo.Attributes = file.SourceCopyFileAttributes{}
+		smbInfo.CreationTime = file.SourceCopyFileCreationTime{}
+		smbInfo.LastWriteTime = file.SourceCopyFileLastWriteTime{}
+		smbInfo.ChangeTime = file.SourceCopyFileChangeTime{}
+		smbInfo.PermissionCopyMode = to.Ptr(file.PermissionCopyModeTypeSource)
+	}
+
+	if hasAttributes {
+		attrs, err := file.ParseNTFSFileAttributes(&attributes)
+		if err != nil {
+			return nil, err
+		}
+		smbInfo.Attributes = file.DestinationCopyFileAttributes(*attrs)
+	}
+	if hasPermission || hasPermissionKey {
+		var err error
+		options.Permissions, err = s.formatFilePermission(ctx, hasPermission, permission, hasPermissionKey, permissionKey)
+		if err != nil {
+			return nil, err
+		}
+		smbInfo.PermissionCopyMode = to.Ptr(file.PermissionCopyModeTypeOverride)
+	}
+	options.CopyFileSMBInfo = smbInfo
+
+	return options, nil
+}
+
+// sourceFileClient will return the client of the file in another share of the same account.
+//
+// The path is resolved from the root of the share, the work dir is not applied.
+func (s *Storage) sourceFileClient(shareName string, path string) *file.Client {
+	dir, name := pathpkg.Split(strings.TrimPrefix(path, "/"))
+	return subdirectoryClient(s.service.NewShareClient(shareName).NewRootDirectoryClient(), dir).NewFileClient(name)
+}
+
+// startCopy will start a server-side copy from source to dst and wait until the copy finished.
+//
+// progress will be called with the latest status while the copy is pending, and the
+// pending copy will be aborted if ctx is canceled.
+func (s *Storage) startCopy(ctx context.Context, source string, dst string, options *file.StartCopyFromURLOptions, progress CopyProgressFunc) error {
+	dstClient := s.fileClient(dst)
+
+	// StartCopyFromURL is asynchronous, the copy could still be pending after it returns.
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/copy-file
+	var output file.StartCopyFromURLResponse
+	err := s.withParentDirs(ctx, dst, func() (err error) {
+		output, err = dstClient.StartCopyFromURL(ctx, source, options)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var status file.CopyStatusType
+	if output.CopyStatus != nil {
+		status = *output.CopyStatus
+	}
+	for status == file.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			// Don't leave the copy running after caller gave up, the abort is best effort.
+			if output.CopyID != nil {
+				_, _ = dstClient.AbortCopy(context.Background(), *output.CopyID, nil)
+			}
+			return ctx.Err()
+		case <-time.After(copyPollInterval):
+		}
+
+		fi, err := dstClient.GetProperties(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		status = ""
+		if fi.CopyStatus != nil {
+			status = *fi.CopyStatus
+		}
+		if progress != nil {
+			cs, err := parseCopyStatus(fi.CopyID, fi.CopyStatus, fi.CopyStatusDescription, fi.CopyProgress)
+			if err != nil {
+				return err
+			}
+			progress(cs)
+		}
+		if status != file.CopyStatusTypePending && status != file.CopyStatusTypeSuccess {
+			var desc string
+			if fi.CopyStatusDescription != nil {
+				desc = *fi.CopyStatusDescription
+			}
+			return fmt.Errorf("%w: %s, %s", ErrCopyFailed, status, desc)
+		}
+	}
+
+	if status != file.CopyStatusTypeSuccess {
+		return fmt.Errorf("%w: %s", ErrCopyFailed, status)
+	}
+
+	return nil
 }
 
 func (s *Storage) newObject(done bool) *types.Object {
 	return types.NewObject(s, done)
 }
 
-func (s *Storage) formatFileObject(v azfile.FileItem) (o *types.Object, err error) {
-	o = s.newObject(true)
-	o.ID = v.Name
-	o.Path = s.getRelPath(v.Name)
+func (s *Storage) formatFileObject(dir string, v *directory.File) (o *types.Object, err error) {
+	// The files not stored by the path mapper are skipped.
+	path, ok := s.unmapPath(dir + *v.Name)
+	if !ok {
+		return nil, nil
+	}
+
+	// List doesn't return metadata of files, so we leave the object undone to
+	// stat it while accessing the metadata.
+	o = s.newObject(false)
+	o.ID = s.getAbsPath(path)
+	o.Path = path
 	o.Mode |= types.ModeRead
 
-	if v.Properties.ContentLength != 0 {
-		o.SetContentLength(v.Properties.ContentLength)
+	if v.Properties != nil && v.Properties.ContentLength != nil {
+		o.SetContentLength(*v.Properties.ContentLength)
+
+		// Any file could be appended by resizing it. Encrypted files and virtual links
+		// are only known by stat, so listed files are not appendable while they could exist.
+		if s.encryptionKey == nil && !s.features.VirtualLink {
+			o.Mode |= types.ModeAppend
+			o.SetAppendOffset(*v.Properties.ContentLength)
+		}
 	}
+	formatListedProperties(o, v.Properties, smbProperties{
+		attributes:    v.Attributes,
+		id:            v.ID,
+		permissionKey: v.PermissionKey,
+	})
 
 	return
 }
 
-func (s *Storage) formatDirObject(v azfile.DirectoryItem) (o *types.Object, err error) {
+func (s *Storage) formatDirObject(dir string, v *directory.Directory) (o *types.Object, err error) {
 	o = s.newObject(true)
-	o.ID = v.Name
-	o.Path = s.getRelPath(v.Name)
+	o.ID = s.getAbsPath(dir + *v.Name)
+	o.Path = dir + *v.Name
 	o.Mode |= types.ModeDir
 
+	formatListedProperties(o, v.Properties, smbProperties{
+		attributes:    v.Attributes,
+		id:            v.ID,
+		permissionKey: v.PermissionKey,
+	})
+
 	return
 }
 
+// formatListedProperties will set the properties returned by listing with extended info.
+func formatListedProperties(o *types.Object, p *directory.FileProperty, v smbProperties) {
+	if p != nil {
+		if p.LastModified != nil {
+			o.SetLastModified(*p.LastModified)
+		}
+		if p.ETag != nil {
+			o.SetEtag(string(*p.ETag))
+		}
+		v.changeTime = p.ChangeTime
+		v.creationTime = p.CreationTime
+		v.lastWriteTime = p.LastWriteTime
+	}
+
+	// The file id is always returned with extended info.
+	if v.id == nil {
+		return
+	}
+	var sm ObjectSystemMetadata
+	formatSMBProperties(&sm, v)
+	o.SetSystemMetadata(sm)
+}
+
+// smbProperties is the SMB properties returned by both file and directory GetProperties.
+type smbProperties struct {
+	attributes    *string
+	changeTime    *time.Time
+	creationTime  *time.Time
+	id            *string
+	lastWriteTime *time.Time
+	parentID      *string
+	permissionKey *string
+}
+
+func formatSMBProperties(sm *ObjectSystemMetadata, v smbProperties) {
+	if v.attributes != nil {
+		sm.FileAttributes = *v.attributes
+	}
+	if v.id != nil {
+		sm.FileID = *v.id
+	}
+	if v.parentID != nil {
+		sm.ParentID = *v.parentID
+	}
+	if v.permissionKey != nil {
+		sm.FilePermissionKey = *v.permissionKey
+	}
+	if v.changeTime != nil {
+		sm.FileChangeTime = *v.changeTime
+	}
+	if v.creationTime != nil {
+		sm.FileCreationTime = *v.creationTime
+	}
+	if v.lastWriteTime != nil {
+		sm.FileLastWriteTime = *v.lastWriteTime
+	}
+}
+
+// formatMetadata will convert user metadata into the metadata required by SDK.
+func formatMetadata(m map[string]string) map[string]*string {
+	if m == nil {
+		return nil
+	}
+
+	metadata := make(map[string]*string, len(m))
+	for k, v := range m {
+		v := v
+		metadata[k] = &v
+	}
+	return metadata
+}
+
+// parseMetadata will convert the metadata returned by SDK into user metadata.
+//
+// The keys are canonicalized as http headers in the response, so we lower them.
+func parseMetadata(m map[string]*string) map[string]string {
+	metadata := make(map[string]string, len(m))
+	for k, v := range m {
+		if v != nil {
+			metadata[strings.ToLower(k)] = *v
+		}
+	}
+	return metadata
+}
+
+// parseUserMetadata will parse the metadata of files and directories, and hide the
+// metadata used by client-side encryption.
+func parseUserMetadata(m map[string]*string) map[string]string {
+	metadata := parseMetadata(m)
+	delete(metadata, metadataEncryptionKey)
+	delete(metadata, metadataEncryptionKeyID)
+	delete(metadata, metadataEncryptionSize)
+	return metadata
+}
+
+// formatLeaseAccessConditions will return nil if the lease id is not set.
+func formatLeaseAccessConditions(has bool, leaseID string) *file.LeaseAccessConditions {
+	if !has {
+		return nil
+	}
+	return &file.LeaseAccessConditions{LeaseID: &leaseID}
+}
+
+// formatFilePermission will set the permission into SMB properties.
+//
+// The permission in SDDL could be carried by header only if it's not larger than 8 KiB,
+// otherwise we need to create the permission on the share and use its key instead.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/create-permission
+func (s *Storage) formatFilePermission(ctx context.Context, hasPermission bool, permission string, hasKey bool, key string) (*file.Permissions, error) {
+	if hasKey {
+		return &file.Permissions{PermissionKey: &key}, nil
+	}
+	if !hasPermission {
+		return nil, nil
+	}
+
+	if len(permission) <= maxFilePermissionHeaderSize {
+		return &file.Permissions{Permission: &permission}, nil
+	}
+
+	output, err := s.share.CreatePermission(ctx, permission, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file.Permissions{PermissionKey: output.FilePermissionKey}, nil
+}
+
+// getFilePermission will get the permission in SDDL by its key.
+func (s *Storage) getFilePermission(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+
+	output, err := s.share.GetPermission(ctx, key, nil)
+	if err != nil {
+		return "", err
+	}
+	if output.Permission == nil {
+		return "", nil
+	}
+	return *output.Permission, nil
+}
+
+// dirClient will return the client of the directory, all requests should be sent by the
+// clients returned by dirClient and fileClient to make sure the work dir is applied.
+//
+// All paths are resolved from work dir, including the paths start with "/".
+func (s *Storage) dirClient(path string) *directory.Client {
+	return subdirectoryClient(s.client, s.normalizePath(path))
+}
+
+// fileClient will return the client of the file, see dirClient for the path resolving.
+func (s *Storage) fileClient(path string) *file.Client {
+	dir, name := pathpkg.Split(s.mapPath(path))
+	return s.dirClient(dir).NewFileClient(name)
+}
+
+// subdirectoryClient will return the client of the sub directory in dir.
+//
+// SDK escapes the "/" in names, so we need to walk into the directory level by level.
+func subdirectoryClient(dir *directory.Client, path string) *directory.Client {
+	for _, v := range strings.Split(path, "/") {
+		if v == "" || v == "." {
+			continue
+		}
+		dir = dir.NewSubdirectoryClient(v)
+	}
+	return dir
+}
+
+// listFilesAndDirectories will list one page of the directory in page status.
+func (s *Storage) listFilesAndDirectories(ctx context.Context, input *objectPageStatus) (directory.ListFilesAndDirectoriesResponse, error) {
+	options := &directory.ListFilesAndDirectoriesOptions{
+		Marker:     input.marker,
+		MaxResults: &input.maxResults,
+	}
+	if input.prefix != "" {
+		options.Prefix = &input.prefix
+	}
+	if input.extendedInfo {
+		// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/list-directories-and-files
+		options.Include = directory.ListFilesInclude{
+			Timestamps:    true,
+			ETag:          true,
+			Attributes:    true,
+			PermissionKey: true,
+		}
+		options.IncludeExtendedInfo = to.Ptr(true)
+	} else if input.filter.timestamps() {
+		options.Include.Timestamps = true
+	}
+
+	// The timeout is applied to every page instead of the whole listing, because
+	// pages are fetched lazily.
+	ctx = withClientRequestID(ctx, input.clientRequestID != "", input.clientRequestID)
+	ctx = withCallOptions(ctx, input.hasCallOptions, input.callOptions)
+	ctx, cancel := withTimeout(ctx, input.timeout > 0, input.timeout)
+	defer cancel()
+
+	// Pager will be created for every page, the marker is kept in page status.
+	return s.dirClient(input.dir).NewListFilesAndDirectoriesPager(options).NextPage(ctx)
+}
+
+type listResult struct {
+	output directory.ListFilesAndDirectoriesResponse
+	err    error
+}
+
+// nextListPage will return the page of input.dir from input.marker, and start to
+// fetch the following page in background while the current page is being consumed.
+func (s *Storage) nextListPage(ctx context.Context, input *objectPageStatus) (directory.ListFilesAndDirectoriesResponse, error) {
+	var res listResult
+	if input.prefetch != nil {
+		select {
+		case res = <-input.prefetch:
+		case <-ctx.Done():
+			return res.output, ctx.Err()
+		}
+		input.prefetch = nil
+	} else {
+		res.output, res.err = s.listFilesAndDirectories(ctx, input)
+	}
+	if res.err != nil {
+		return res.output, res.err
+	}
+
+	// Only pages of the same directory are prefetched, the next directory to walk
+	// is decided by the consumer in prefix mode.
+	if v := res.output.NextMarker; v != nil && *v != "" {
+		next := &objectPageStatus{
+			maxResults:      input.maxResults,
+			prefix:          input.prefix,
+			marker:          v,
+			timeout:         input.timeout,
+			clientRequestID: input.clientRequestID,
+			hasCallOptions:  input.hasCallOptions,
+			callOptions:     input.callOptions,
+			extendedInfo:    input.extendedInfo,
+			filter:          input.filter,
+			dir:             input.dir,
+		}
+		// The channel is buffered, so the goroutine will exit even if the iterator is abandoned.
+		ch := make(chan listResult, 1)
+		go func() {
+			output, err := s.listFilesAndDirectories(ctx, next)
+			ch <- listResult{output: output, err: err}
+		}()
+		input.prefetch = ch
+	}
+
+	return res.output, nil
+}
+
+// isDirPath will check whether the path refers to a directory.
+//
+// Without object mode, a path ends with "/" is treated as a directory.
+func isDirPath(path string, hasMode bool, mode types.ObjectMode) bool {
+	if hasMode {
+		return mode.IsDir()
+	}
+	return strings.HasSuffix(path, "/")
+}
+
+// formatDirPath will make sure the non-empty dir path ends with "/".
+func formatDirPath(path string) string {
+	if path == "" || path == "." || path == "/" {
+		return ""
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return path
+}
+
 const (
 	// File not found error.
 	fileNotFound = 404
 )
 
+const (
+	// maxRangeSize is the maximum size of a range which could be written by UploadRange.
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/put-range
+	maxRangeSize = 4 * 1024 * 1024
+
+	// maxFilePermissionHeaderSize is the maximum size of the permission carried by x-ms-file-permission.
+	maxFilePermissionHeaderSize = 8 * 1024
+
+	// defaultPartSize is the default size of every part in multipart upload.
+	defaultPartSize = 64 * 1024 * 1024
+
+	// copyPollInterval is the interval between two copy status checks.
+	copyPollInterval = 500 * time.Millisecond
+
+	// defaultListPageSize is the default number of entries returned in one list page.
+	defaultListPageSize = 200
+	// maxListPageSize is the maximum number of entries returned in one list page.
+	// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/list-directories-and-files
+	maxListPageSize = 5000
+
+	// defaultEndpointSuffix is the endpoint suffix of Azure public cloud.
+	defaultEndpointSuffix = "core.windows.net"
+
+	// metadataLinkTarget is the metadata key which carries the target of virtual links.
+	metadataLinkTarget = "bm_link_target"
+)
+
+var (
+	// ErrCopyFailed will be returned while the server-side copy is failed or aborted.
+	ErrCopyFailed = services.NewErrorCode("copy failed")
+	// ErrSharedKeyRequired will be returned while signing requests without shared key credential.
+	ErrSharedKeyRequired = services.NewErrorCode("shared key required")
+	// ErrContentMD5Mismatch will be returned while the downloaded content doesn't match its MD5.
+	ErrContentMD5Mismatch = services.NewErrorCode("content md5 mismatch")
+	// ErrContentLengthMismatch will be returned while the size of the transferred file doesn't match its source.
+	ErrContentLengthMismatch = services.NewErrorCode("content length mismatch")
+	// ErrContentCRC64Mismatch will be returned while the CRC64 returned by service doesn't match the uploaded content.
+	ErrContentCRC64Mismatch = services.NewErrorCode("content crc64 mismatch")
+	// ErrConditionNotMet will be returned while the preconditions of the operation are not met.
+	ErrConditionNotMet = services.NewErrorCode("condition not met")
+	// ErrEncryptionKeyNotFound will be returned while the key which encrypted the file could not be found.
+	ErrEncryptionKeyNotFound = services.NewErrorCode("encryption key not found")
+	// ErrInvalidPath will be returned while the path is not allowed by Azure Files.
+	ErrInvalidPath = services.NewErrorCode("invalid path")
+	// ErrReadOnly will be returned while calling mutating operations on a read only storage.
+	ErrReadOnly = services.NewErrorCode("storage is read only")
+	// ErrShareQuotaExceeded will be returned while the content to write exceeds the quota of share.
+	ErrShareQuotaExceeded = services.NewErrorCode("share quota exceeded")
+)
+
 func checkError(err error, expect int) bool {
-	e, ok := err.(azfile.StorageError)
-	if !ok {
+	var e *azcore.ResponseError
+	if !errors.As(err, &e) {
 		return false
 	}
 
-	return e.Response().StatusCode == expect
+	return e.StatusCode == expect
+}
+
+func formatMultipartID(partSize int64) string {
+	return strconv.FormatInt(partSize, 10)
+}
+
+func parseMultipartID(id string) (partSize int64, err error) {
+	partSize, err = strconv.ParseInt(id, 10, 64)
+	if err != nil || partSize <= 0 {
+		return 0, fmt.Errorf("multipart id %s is invalid", id)
+	}
+	return partSize, nil
+}
+
+func parseChunkSize(has bool, v int64) (int64, error) {
+	if !has {
+		return maxRangeSize, nil
+	}
+	if v <= 0 || v > maxRangeSize {
+		return 0, fmt.Errorf("chunk size %d is out of range (0, %d]", v, maxRangeSize)
+	}
+	return v, nil
+}
+
+// deref will return the value v points to, or the zero value if v is nil.
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+
+// parseConcurrency will return 1 if concurrency is not set or invalid.
+func parseConcurrency(has bool, v int) int {
+	if !has || v < 1 {
+		return 1
+	}
+	return v
+}
+
+// uploadRanges will upload size bytes read from r into the file start from offset.
+//
+// The content will be split into ranges no larger than chunkSize, and every range
+// will be sent with its transactional MD5.
+//
+// If useCRC64 is true, the transactional CRC64 will be sent instead of MD5.
+//
+// At most concurrency ranges will be uploaded and held in memory at the same time.
+func (s *Storage) uploadRanges(ctx context.Context, client *file.Client, offset int64, r io.Reader, size int64, chunkSize int64, concurrency int, useCRC64 bool, lease *file.LeaseAccessConditions) error {
+	if size <= 0 {
+		return nil
+	}
+
+	if size < chunkSize {
+		chunkSize = size
+	}
+
+	pool, ctx := newWorkerPool(ctx, concurrency)
+
+	for size > 0 {
+		n := chunkSize
+		if size < n {
+			n = size
+		}
+
+		// Ranges are uploaded concurrently, so every range needs its own buffer.
+		buf, err := s.buffers.acquire(ctx, n)
+		if err != nil {
+			pool.setError(err)
+			break
+		}
+		_, err = io.ReadFull(r, buf)
+		if err != nil {
+			s.buffers.release(buf)
+			pool.setError(err)
+			break
+		}
+
+		rangeOffset := offset
+		ok := pool.Go(ctx, func() error {
+			defer s.buffers.release(buf)
+			return uploadRange(ctx, client, rangeOffset, buf, useCRC64, lease)
+		})
+		if !ok {
+			s.buffers.release(buf)
+			break
+		}
+
+		offset += n
+		size -= n
+	}
+
+	return pool.Wait()
+}
+
+// uploadStream will upload the content read from r until EOF, the file will be
+// resized to the size of uploaded content.
+//
+// The file is grown before ranges are uploaded, because UploadRange could not
+// write beyond the end of file.
+func (s *Storage) uploadStream(ctx context.Context, client *file.Client, r io.Reader, chunkSize int64, concurrency int, useCRC64 bool, lease *file.LeaseAccessConditions) (n int64, err error) {
+	pool, poolCtx := newWorkerPool(ctx, concurrency)
+
+	// Double the allocated size every time, so that we don't resize for every chunk.
+	var allocated int64
+	for {
+		buf, err := s.buffers.acquire(poolCtx, chunkSize)
+		if err != nil {
+			pool.setError(err)
+			break
+		}
+		read, rerr := io.ReadFull(r, buf)
+		if read > 0 {
+			if n+int64(read) > allocated {
+				allocated = 2 * (n + int64(read))
+				_, err = client.Resize(poolCtx, allocated, &file.ResizeOptions{
+					LeaseAccessConditions: lease,
+				})
+				if err != nil {
+					s.buffers.release(buf)
+					pool.setError(err)
+					break
+				}
+			}
+
+			rangeOffset, data := n, buf[:read]
+			ok := pool.Go(poolCtx, func() error {
+				defer s.buffers.release(buf)
+				return uploadRange(poolCtx, client, rangeOffset, data, useCRC64, lease)
+			})
+			if !ok {
+				s.buffers.release(buf)
+				break
+			}
+			n += int64(read)
+		} else {
+			s.buffers.release(buf)
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			pool.setError(rerr)
+			break
+		}
+	}
+
+	err = pool.Wait()
+	if err != nil {
+		return n, err
+	}
+
+	// Truncate the preallocated space, the context of pool has been canceled after waited.
+	if allocated != n {
+		_, err = client.Resize(ctx, n, &file.ResizeOptions{
+			LeaseAccessConditions: lease,
+		})
+	}
+	return n, err
+}
+
+// uploadRange will upload data into the file at offset with its transactional checksum.
+func uploadRange(ctx context.Context, client *file.Client, offset int64, data []byte, useCRC64 bool, lease *file.LeaseAccessConditions) error {
+	options := &file.UploadRangeOptions{
+		LeaseAccessConditions: lease,
+	}
+
+	if useCRC64 {
+		ctx = withContentCRC64(ctx, data)
+	} else {
+		sum := md5.Sum(data)
+		options.TransactionalValidation = file.TransferValidationTypeMD5(sum[:])
+	}
+
+	_, err := client.UploadRange(ctx, offset, streaming.NopCloser(bytes.NewReader(data)), options)
+	return err
+}
+
+type rangeResult struct {
+	data []byte
+	err  error
+}
+
+// downloadRanges will download count bytes start from offset in ranges concurrently,
+// and write them into w in order.
+//
+// If verify is true, the content of every range will be verified with the MD5 returned by service.
+//
+// At most concurrency ranges will be held in memory at the same time.
+func (s *Storage) downloadRanges(ctx context.Context, client *file.Client, w io.Writer, offset, count, rangeSize int64, concurrency int, verify bool, retries int) (n int64, err error) {
+	if count <= 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan rangeResult, (count+rangeSize-1)/rangeSize)
+	for i := range results {
+		results[i] = make(chan rangeResult, 1)
+	}
+	// sem will be released after the range has been written into w.
+	sem := make(chan struct{}, concurrency)
+
+	go func() {
+		for i := range results {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			start := offset + int64(i)*rangeSize
+			size := rangeSize
+			if start+size > offset+count {
+				size = offset + count - start
+			}
+
+			go func(i int, start, size int64) {
+				data, err := s.downloadRange(ctx, client, start, size, verify, retries)
+				results[i] <- rangeResult{data: data, err: err}
+			}(i, start, size)
+		}
+	}()
+
+	for i := range results {
+		var res rangeResult
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+		if res.err != nil {
+			return n, res.err
+		}
+
+		written, err := w.Write(res.data)
+		s.buffers.put(res.data)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+
+		<-sem
+	}
+
+	return n, nil
+}
+
+// downloadRange will download the range into a buffer from pool, the range will be
+// requested again from where its body failed to be read, up to retries times.
+func (s *Storage) downloadRange(ctx context.Context, client *file.Client, offset, size int64, verify bool, retries int) (data []byte, err error) {
+	// The buffer should be returned to pool by caller after consumed.
+	data = s.buffers.get(size)
+	defer func() {
+		if err != nil {
+			s.buffers.put(data)
+			data = nil
+		}
+	}()
+
+	var etag *azcore.ETag
+	var read int64
+	for i := 0; ; i++ {
+		options := &file.DownloadStreamOptions{
+			Range: file.HTTPRange{Offset: offset + read, Count: size - read},
+		}
+		if verify {
+			options.RangeGetContentMD5 = to.Ptr(true)
+		}
+
+		// The SDK doesn't expose the conditions of Get File, so If-Match is set by header.
+		rctx := ctx
+		if etag != nil {
+			rctx = policy.WithHTTPHeader(ctx, http.Header{"If-Match": []string{string(*etag)}})
+		}
+
+		// The request has been retried by pipeline if it failed.
+		output, err := client.DownloadStream(rctx, options)
+		if err != nil {
+			// The file has been changed since the range was requested first.
+			if fileerror.HasCode(err, fileerror.ConditionNotMet) {
+				return nil, fmt.Errorf("%w: file has been changed while reading", ErrConditionNotMet)
+			}
+			return nil, err
+		}
+		if etag == nil {
+			etag = output.ETag
+		}
+
+		n, err := io.ReadFull(output.Body, data[read:])
+		_ = output.Body.Close()
+		read += int64(n)
+		if err == nil {
+			if verify {
+				sum := md5.Sum(data)
+				if !bytes.Equal(sum[:], output.ContentMD5) {
+					return nil, fmt.Errorf("%w: range %d-%d", ErrContentMD5Mismatch, offset, offset+size-1)
+				}
+			}
+			return data, nil
+		}
+
+		if i >= retries || ctx.Err() != nil {
+			return nil, err
+		}
+		// The MD5 returned by service is of the requested range, so the range should be
+		// requested from the start to be verified.
+		if verify {
+			read = 0
+		}
+	}
 }
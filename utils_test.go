@@ -0,0 +1,84 @@
+package azfile
+
+import (
+	"testing"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// newTestStorage will create a storage with shared key, no request is sent.
+func newTestStorage(t *testing.T, workDir string, pairs ...types.Pair) *Storage {
+	t.Helper()
+
+	_, store, err := newServicerAndStorager(append([]types.Pair{
+		ps.WithEndpoint("https:account.file.core.windows.net"),
+		ps.WithCredential("hmac:account:a2V5"),
+		ps.WithName("share"),
+		ps.WithWorkDir(workDir),
+	}, pairs...)...)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+	return store
+}
+
+func TestGetAbsPath(t *testing.T) {
+	cases := []struct {
+		name     string
+		workDir  string
+		path     string
+		expected string
+	}{
+		{"root work dir", "/", "abc", "abc"},
+		{"root work dir with dir", "/", "abc/", "abc/"},
+		{"nested work dir", "/a/b/c/", "abc", "a/b/c/abc"},
+		{"nested work dir with nested path", "/a/b/c/", "d/e/abc", "a/b/c/d/e/abc"},
+		{"nested work dir with dir", "/a/b/c/", "d/", "a/b/c/d/"},
+		{"nested work dir itself", "/a/b/c/", "", "a/b/c/"},
+		{"work dir without slashes", "a/b", "abc", "a/b/abc"},
+		{"absolute path in root work dir", "/", "/abc", "abc"},
+		{"absolute path confined to nested work dir", "/a/b/c/", "/x/y", "a/b/c/x/y"},
+		{"absolute root confined to nested work dir", "/a/b/c/", "/", "a/b/c/"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, tt.workDir)
+
+			if got := s.getAbsPath(tt.path); got != tt.expected {
+				t.Errorf("getAbsPath(%q) with work dir %q = %q, expected %q", tt.path, tt.workDir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClientURL(t *testing.T) {
+	const shareURL = "https://account.file.core.windows.net/share/"
+
+	cases := []struct {
+		name     string
+		workDir  string
+		path     string
+		expected string
+	}{
+		{"root work dir", "/", "abc", "abc"},
+		{"nested work dir", "/a/b/c/", "abc", "a/b/c/abc"},
+		{"nested work dir with nested path", "/a/b/c/", "d/e/abc", "a/b/c/d/e/abc"},
+		{"work dir without slashes", "a/b", "d/abc", "a/b/d/abc"},
+		{"absolute path confined to nested work dir", "/a/b/c/", "/x/y", "a/b/c/x/y"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, tt.workDir)
+
+			if got := s.fileClient(tt.path).URL(); got != shareURL+tt.expected {
+				t.Errorf("fileClient(%q) with work dir %q = %q, expected %q", tt.path, tt.workDir, got, shareURL+tt.expected)
+			}
+			if got := s.dirClient(tt.path).URL(); got != shareURL+tt.expected {
+				t.Errorf("dirClient(%q) with work dir %q = %q, expected %q", tt.path, tt.workDir, got, shareURL+tt.expected)
+			}
+		})
+	}
+}
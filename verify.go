@@ -0,0 +1,108 @@
+package azfile
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
+)
+
+// TransferMismatchError will be returned while the destination of a transfer doesn't
+// match its source, it wraps ErrContentLengthMismatch or ErrContentMD5Mismatch.
+type TransferMismatchError struct {
+	Path string
+	// Field is "content-length" or "content-md5".
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (e *TransferMismatchError) Error() string {
+	return fmt.Sprintf("%s of %s mismatch: expected %s, actual %s", e.Field, e.Path, e.Expected, e.Actual)
+}
+
+func (e *TransferMismatchError) Unwrap() error {
+	if e.Field == "content-length" {
+		return ErrContentLengthMismatch
+	}
+	return ErrContentMD5Mismatch
+}
+
+// verifyTransfer will check the size and content of the file against the source.
+//
+// The whole file is downloaded to compute its MD5, since service doesn't compute the
+// MD5 of files and the Content-MD5 property is only what the client claimed.
+func (s *Storage) verifyTransfer(ctx context.Context, path string, client *file.Client, size int64, sum []byte) error {
+	output, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if actual := *output.ContentLength; actual != size {
+		return &TransferMismatchError{
+			Path:     path,
+			Field:    "content-length",
+			Expected: strconv.FormatInt(size, 10),
+			Actual:   strconv.FormatInt(actual, 10),
+		}
+	}
+
+	actual, err := s.fileMD5(ctx, client)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actual, sum) {
+		return newContentMD5MismatchError(path, sum, actual)
+	}
+	return nil
+}
+
+// verifyCopy will check the destination of a server-side copy against the source, the
+// Content-MD5 of source is trusted if it's set, otherwise the source will be downloaded too.
+func (s *Storage) verifyCopy(ctx context.Context, src *file.Client, dst string) error {
+	output, err := src.GetProperties(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sum := output.ContentMD5
+	if len(sum) == 0 {
+		sum, err = s.fileMD5(ctx, src)
+		if err != nil {
+			return err
+		}
+	}
+	return s.verifyTransfer(ctx, dst, s.fileClient(dst), *output.ContentLength, sum)
+}
+
+// fileMD5 will download the whole file to compute its MD5.
+func (s *Storage) fileMD5(ctx context.Context, client *file.Client) ([]byte, error) {
+	rs := &readStream{
+		ctx:     ctx,
+		cancel:  func() {},
+		client:  client,
+		retries: defaultReadRetries,
+	}
+	defer rs.Close()
+
+	h := md5.New()
+	_, err := io.Copy(h, s.limitReader(ctx, rs))
+	if err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func newContentMD5MismatchError(path string, expected, actual []byte) error {
+	return &TransferMismatchError{
+		Path:     path,
+		Field:    "content-md5",
+		Expected: base64.StdEncoding.EncodeToString(expected),
+		Actual:   base64.StdEncoding.EncodeToString(actual),
+	}
+}
@@ -0,0 +1,27 @@
+package azfile
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// headerVersion is the header carrying the service version of the request.
+//
+// ref: https://docs.microsoft.com/en-us/rest/api/storageservices/versioning-for-the-azure-storage-services
+const headerVersion = "x-ms-version"
+
+// apiVersionPolicy will override the service version set by SDK.
+//
+// Older service versions don't support the headers introduced later, the service
+// will ignore or reject them, so features like file request intent and trailing dot
+// should not be used with an older version.
+type apiVersionPolicy struct {
+	version string
+}
+
+// Do implements policy.Policy
+func (p apiVersionPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.Raw().Header.Set(headerVersion, p.version)
+	return req.Next()
+}
@@ -0,0 +1,86 @@
+package azfile
+
+import (
+	"context"
+	pathpkg "path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/fileerror"
+)
+
+// withParentDirs will call fn, and if the parent directory of path doesn't exist
+// while virtual_dir is enabled, create all missing parent directories and call fn again.
+//
+// Parent directories are only created on failure, so that writing into an existing
+// directory doesn't cost extra requests.
+func (s *Storage) withParentDirs(ctx context.Context, path string, fn func() error) error {
+	return s.withParentDirsIf(ctx, s.features.VirtualDir, path, fn)
+}
+
+// withParentDirsIf is the same as withParentDirs, but parent directories are only
+// created if enabled is true.
+func (s *Storage) withParentDirsIf(ctx context.Context, enabled bool, path string, fn func() error) error {
+	err := fn()
+	if err == nil || !enabled || !fileerror.HasCode(err, fileerror.ParentNotFound) {
+		return err
+	}
+
+	// Only the paths of files are created with parents, which are stored at the mapped path.
+	err = s.createParentDirs(ctx, s.mapPath(path))
+	if err != nil {
+		return err
+	}
+	return fn()
+}
+
+// createParentDirs will create all parent directories of path from the top level,
+// the directories which already exist are skipped.
+func (s *Storage) createParentDirs(ctx context.Context, path string) error {
+	dir := strings.TrimSuffix(pathpkg.Dir(strings.TrimSuffix(path, "/")), "/")
+	if dir == "." || dir == "" {
+		return nil
+	}
+
+	var prefix string
+	if strings.HasPrefix(dir, "/") {
+		prefix = "/"
+	}
+	for _, name := range strings.Split(strings.TrimPrefix(dir, "/"), "/") {
+		if name == "" || name == "." {
+			continue
+		}
+		prefix += name + "/"
+
+		_, err := s.dirClient(prefix).Create(ctx, nil)
+		// The directory could be created by others at the same time.
+		if err != nil && !fileerror.HasCode(err, fileerror.ResourceAlreadyExists) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEmptyParentDirs will delete the parent directories of path which become
+// empty, until the work dir is reached, so that the directories materialized by
+// virtual_dir don't outlive the files in them.
+func (s *Storage) deleteEmptyParentDirs(ctx context.Context, path string) error {
+	dir := strings.TrimSuffix(path, "/")
+	for {
+		dir = pathpkg.Dir(dir)
+		if dir == "." || dir == "/" || dir == "" {
+			return nil
+		}
+
+		_, err := s.dirClient(dir).Delete(ctx, nil)
+		if err == nil {
+			s.statCache.invalidate(s.getAbsPath(dir))
+			continue
+		}
+
+		// The directory is still used by other objects, or has been deleted by others.
+		if fileerror.HasCode(err, fileerror.DirectoryNotEmpty) || checkError(err, fileNotFound) {
+			return nil
+		}
+		return err
+	}
+}
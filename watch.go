@@ -0,0 +1,190 @@
+package azfile
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/directory"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Types of WatchEvent.
+const (
+	WatchEventCreate = "create"
+	WatchEventModify = "modify"
+	WatchEventDelete = "delete"
+	// WatchEventError is sent while the directory failed to be listed, the watcher
+	// will keep polling and the changes will be detected once listing succeeds.
+	WatchEventError = "error"
+)
+
+// defaultWatchInterval is the interval between two listings if not specified.
+const defaultWatchInterval = 10 * time.Second
+
+// WatchEvent is a change of the files and directories under the watched directory.
+type WatchEvent struct {
+	Type string
+	// Object is the object after the change, or the object before the change
+	// for delete events. It's nil for error events.
+	Object *types.Object
+	Err    error
+}
+
+// watchEntry is the state of an object in the last listing.
+type watchEntry struct {
+	o            *types.Object
+	etag         string
+	lastModified time.Time
+	size         int64
+}
+
+// Watch will list the directory every interval, and send the changes of the files
+// and directories directly under it into the returned channel.
+//
+// The first listing is used as the baseline and produces no events. Changes
+// are detected by ETag, last modified time and size, so changes made between two
+// listings will be merged into one event. The channel will be closed after ctx is
+// done, and the default interval will be used if interval is not positive.
+func (s *Storage) Watch(ctx context.Context, path string, interval time.Duration) (<-chan WatchEvent, error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	err := s.checkPathMapper("watch")
+	if err != nil {
+		return nil, s.formatError("watch", err, path)
+	}
+
+	dir := formatDirPath(path)
+
+	// Listing the baseline synchronously, so that an invalid path will be reported
+	// to caller directly.
+	last, err := s.watchList(ctx, dir)
+	if err != nil {
+		return nil, s.formatError("watch", err, path)
+	}
+
+	ch := make(chan WatchEvent)
+	go func() {
+		defer close(ch)
+
+		send := func(e WatchEvent) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			current, err := s.watchList(ctx, dir)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if !send(WatchEvent{Type: WatchEventError, Err: s.formatError("watch", err, path)}) {
+					return
+				}
+				continue
+			}
+
+			for name, e := range current {
+				old, ok := last[name]
+				if !ok {
+					if !send(WatchEvent{Type: WatchEventCreate, Object: e.o}) {
+						return
+					}
+					continue
+				}
+				if old.etag != e.etag || !old.lastModified.Equal(e.lastModified) || old.size != e.size {
+					if !send(WatchEvent{Type: WatchEventModify, Object: e.o}) {
+						return
+					}
+				}
+			}
+			for name, e := range last {
+				if _, ok := current[name]; !ok {
+					if !send(WatchEvent{Type: WatchEventDelete, Object: e.o}) {
+						return
+					}
+				}
+			}
+
+			last = current
+		}
+	}()
+
+	return ch, nil
+}
+
+// watchList will list all files and directories under dir, keyed by name.
+//
+// Directories are suffixed with "/", so that a file replaced by a directory of the
+// same name will be reported as a delete and a create.
+func (s *Storage) watchList(ctx context.Context, dir string) (map[string]watchEntry, error) {
+	entries := make(map[string]watchEntry)
+
+	pager := s.dirClient(dir).NewListFilesAndDirectoriesPager(&directory.ListFilesAndDirectoriesOptions{
+		Include: directory.ListFilesInclude{
+			ETag:       true,
+			Timestamps: true,
+		},
+		MaxResults: to.Ptr(int32(maxListPageSize)),
+	})
+	for pager.More() {
+		output, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.Segment.Directories {
+			o, err := s.formatDirObject(dir, v)
+			if err != nil {
+				return nil, err
+			}
+
+			e := watchEntry{o: o}
+			if v.Properties != nil {
+				if v.Properties.ETag != nil {
+					e.etag = string(*v.Properties.ETag)
+				}
+				if v.Properties.LastModified != nil {
+					e.lastModified = *v.Properties.LastModified
+				}
+			}
+			entries[*v.Name+"/"] = e
+		}
+
+		for _, v := range output.Segment.Files {
+			o, err := s.formatFileObject(dir, v)
+			if err != nil {
+				return nil, err
+			}
+
+			e := watchEntry{o: o}
+			if v.Properties != nil {
+				if v.Properties.ETag != nil {
+					e.etag = string(*v.Properties.ETag)
+				}
+				if v.Properties.LastModified != nil {
+					e.lastModified = *v.Properties.LastModified
+				}
+				if v.Properties.ContentLength != nil {
+					e.size = *v.Properties.ContentLength
+				}
+			}
+			entries[*v.Name] = e
+		}
+	}
+
+	return entries, nil
+}